@@ -0,0 +1,183 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fastwego/wxopen"
+)
+
+// 账号管理接口错误码
+const (
+	ErrcodeAlreadyBound    = 89000 // 该公众号/小程序已经绑定了开放平台帐号
+	ErrcodeSubjectMismatch = 89001 // 公众号/小程序与开放平台帐号主体不相同
+	ErrcodeNotBound        = 89002 // 该公众号/小程序未绑定开放平台帐号
+	ErrcodeNotCreatedByApi = 89003 // 该开放平台帐号并非通过 api 创建，不允许操作
+	ErrcodeBindLimit       = 89004 // 该开放平台帐号所绑定的公众号/小程序已达上限（100 个）
+)
+
+// ErrorBoundToOtherAccount 授权方已绑定其他开放平台帐号
+var ErrorBoundToOtherAccount = errors.New("authorizer already bound to another open account")
+
+// BindStatus 授权方绑定开放平台帐号的结果
+type BindStatus int
+
+const (
+	BindStatusAlreadyBound BindStatus = iota // 已绑定在目标开放平台帐号下，无需操作
+	BindStatusCreated                        // 新创建开放平台帐号并绑定
+	BindStatusBound                          // 绑定至目标开放平台帐号
+	BindStatusConflict                       // 冲突：已绑定其他开放平台帐号 / 主体不同等，需人工处理
+	BindStatusFailed                         // 获取 token 或请求接口失败
+)
+
+/*
+BindResult 单个授权方的绑定结果
+
+Status 为 BindStatusConflict / BindStatusFailed 时，Err 为具体错误（接口错误为 *wxopen.ApiError，已绑定其他帐号为 ErrorBoundToOtherAccount）；
+Status 为 BindStatusConflict 且授权方已绑定其他开放平台帐号时，OpenAppid 为其当前绑定的帐号
+*/
+type BindResult struct {
+	Appid     string
+	OpenAppid string
+	Status    BindStatus
+	Err       error
+}
+
+/*
+EnsureBound 确保一组授权方公众号/小程序绑定在同一个开放平台帐号下
+
+- 若其中已有授权方绑定了开放平台帐号，以第一个为准，将其余未绑定的授权方绑定至该帐号
+
+- 若均未绑定，使用第一个授权方创建开放平台帐号，再绑定其余授权方
+
+已绑定其他开放平台帐号、主体不同等情况不做解绑处理，以 BindStatusConflict 结果返回
+
+results 与 appids 顺序一一对应；openAppid 为空表示未能确定目标开放平台帐号
+*/
+func EnsureBound(ctx *wxopen.Platform, appids []string) (openAppid string, results []BindResult) {
+	results = make([]BindResult, len(appids))
+	tokens := make([]string, len(appids))
+	unbound := make([]int, 0, len(appids))
+
+	// 查询各授权方当前绑定的开放平台帐号
+	for i, appid := range appids {
+		results[i].Appid = appid
+
+		token, err := ctx.GetAuthorizerAccessTokenHandler(ctx, appid)
+		if err != nil {
+			results[i].Status = BindStatusFailed
+			results[i].Err = err
+			continue
+		}
+		tokens[i] = token
+
+		bound, err := getOpenAppid(token, appid)
+		switch {
+		case err == nil:
+			results[i].OpenAppid = bound
+			if openAppid == "" {
+				openAppid = bound
+			}
+			if bound == openAppid {
+				results[i].Status = BindStatusAlreadyBound
+			} else {
+				results[i].Status = BindStatusConflict
+				results[i].Err = ErrorBoundToOtherAccount
+			}
+		case isErrcode(err, ErrcodeNotBound):
+			unbound = append(unbound, i)
+		default:
+			results[i].Status = BindStatusFailed
+			results[i].Err = err
+		}
+	}
+
+	for _, i := range unbound {
+		if openAppid == "" {
+			// 均未绑定：创建开放平台帐号
+			created, err := callOpenApi(Create, tokens[i], appids[i], "")
+			if err != nil {
+				results[i].Status, results[i].Err = failedStatus(err), err
+				continue
+			}
+			openAppid = created
+			results[i].OpenAppid = created
+			results[i].Status = BindStatusCreated
+			continue
+		}
+
+		_, err := callOpenApi(Bind, tokens[i], appids[i], openAppid)
+		if err != nil {
+			results[i].Status, results[i].Err = failedStatus(err), err
+			continue
+		}
+		results[i].OpenAppid = openAppid
+		results[i].Status = BindStatusBound
+	}
+
+	return
+}
+
+// getOpenAppid 获取授权方绑定的开放平台帐号
+func getOpenAppid(token string, appid string) (openAppid string, err error) {
+	return callOpenApi(Get, token, appid, "")
+}
+
+// callOpenApi 调用账号管理接口并解析 open_appid / 错误码
+func callOpenApi(api func(string, []byte) ([]byte, error), token string, appid string, openAppid string) (respOpenAppid string, err error) {
+	request := struct {
+		Appid     string `json:"appid"`
+		OpenAppid string `json:"open_appid,omitempty"`
+	}{Appid: appid, OpenAppid: openAppid}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+
+	resp, err := api(token, payload)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Errcode   int64  `json:"errcode"`
+		Errmsg    string `json:"errmsg"`
+		OpenAppid string `json:"open_appid"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	if result.Errcode != 0 {
+		err = &wxopen.ApiError{Errcode: result.Errcode, Errmsg: result.Errmsg, Response: resp}
+		return
+	}
+	return result.OpenAppid, nil
+}
+
+// failedStatus 区分冲突与一般失败
+func failedStatus(err error) BindStatus {
+	if isErrcode(err, ErrcodeAlreadyBound) || isErrcode(err, ErrcodeSubjectMismatch) {
+		return BindStatusConflict
+	}
+	return BindStatusFailed
+}
+
+func isErrcode(err error, errcode int64) bool {
+	apiError, ok := err.(*wxopen.ApiError)
+	return ok && apiError.Errcode == errcode
+}
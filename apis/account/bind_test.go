@@ -0,0 +1,113 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestEnsureBound(t *testing.T) {
+	// 授权方 appid => 当前绑定的开放平台帐号
+	bound := map[string]string{
+		"APPID_BOUND": "OPEN_APPID",
+		"APPID_OTHER": "OTHER_OPEN_APPID",
+	}
+	for _, appid := range []string{"APPID_BOUND", "APPID_OTHER", "APPID_NEW", "APPID_MISMATCH"} {
+		_ = test.MockPlatform.Cache.Save("authorizer_access_token:"+appid, "TOKEN_"+appid, 0)
+	}
+
+	handle := func(w http.ResponseWriter, r *http.Request, fn func(appid, openAppid string) string) {
+		body, _ := ioutil.ReadAll(r.Body)
+		params := struct {
+			Appid     string `json:"appid"`
+			OpenAppid string `json:"open_appid"`
+		}{}
+		_ = json.Unmarshal(body, &params)
+		if r.URL.Query().Get("access_token") != "TOKEN_"+params.Appid {
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		_, _ = w.Write([]byte(fn(params.Appid, params.OpenAppid)))
+	}
+	test.MockSvrHandler.HandleFunc(apiGet, func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, func(appid, _ string) string {
+			if openAppid, ok := bound[appid]; ok {
+				return `{"errcode":0,"errmsg":"ok","open_appid":"` + openAppid + `"}`
+			}
+			return `{"errcode":89002,"errmsg":"open not exists"}`
+		})
+	})
+	test.MockSvrHandler.HandleFunc(apiCreate, func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, func(appid, _ string) string {
+			bound[appid] = "CREATED_OPEN_APPID"
+			return `{"errcode":0,"errmsg":"ok","open_appid":"CREATED_OPEN_APPID"}`
+		})
+	})
+	test.MockSvrHandler.HandleFunc(apiBind, func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, func(appid, openAppid string) string {
+			if strings.Contains(appid, "MISMATCH") {
+				return `{"errcode":89001,"errmsg":"not same contractor"}`
+			}
+			bound[appid] = openAppid
+			return `{"errcode":0,"errmsg":"ok"}`
+		})
+	})
+
+	t.Run("bind to existing", func(t *testing.T) {
+		openAppid, results := EnsureBound(test.MockPlatform, []string{"APPID_NEW", "APPID_BOUND", "APPID_OTHER", "APPID_MISMATCH", "APPID_NO_TOKEN"})
+		if openAppid != "OPEN_APPID" {
+			t.Errorf("EnsureBound() openAppid = %v, want OPEN_APPID", openAppid)
+		}
+
+		got := make([]BindStatus, len(results))
+		for i, result := range results {
+			got[i] = result.Status
+		}
+		want := []BindStatus{BindStatusBound, BindStatusAlreadyBound, BindStatusConflict, BindStatusConflict, BindStatusFailed}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("EnsureBound() statuses = %v, want %v", got, want)
+		}
+		if results[2].OpenAppid != "OTHER_OPEN_APPID" || results[2].Err != ErrorBoundToOtherAccount {
+			t.Errorf("EnsureBound() conflict result = %+v", results[2])
+		}
+		if !isErrcode(results[3].Err, ErrcodeSubjectMismatch) {
+			t.Errorf("EnsureBound() mismatch result = %+v", results[3])
+		}
+		if bound["APPID_NEW"] != "OPEN_APPID" {
+			t.Errorf("EnsureBound() APPID_NEW bound to %v", bound["APPID_NEW"])
+		}
+	})
+
+	t.Run("create when none bound", func(t *testing.T) {
+		delete(bound, "APPID_NEW")
+		openAppid, results := EnsureBound(test.MockPlatform, []string{"APPID_NEW"})
+		if openAppid != "CREATED_OPEN_APPID" || results[0].Status != BindStatusCreated {
+			t.Errorf("EnsureBound() = %v, %+v", openAppid, results)
+		}
+	})
+}
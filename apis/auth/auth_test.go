@@ -12,9 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by cmd from apis.json. DO NOT EDIT.
+
 package auth
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	"os"
 	"reflect"
@@ -24,225 +28,807 @@ import (
 	"github.com/fastwego/wxopen/test"
 )
 
+// 模拟接口错误与令牌过期的响应
+var (
+	errcodeResp = []byte("{\"errcode\":40013,\"errmsg\":\"invalid appid\"}")
+	expiredResp = []byte("{\"errcode\":42001,\"errmsg\":\"access_token expired\"}")
+)
+
 func TestMain(m *testing.M) {
 	test.Setup()
 	os.Exit(m.Run())
 }
 
+func TestCreatePreauthCodeRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID"}`)
+
+	var got CreatePreauthCodeRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode CreatePreauthCodeRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode CreatePreauthCodeRequest error = %v", err)
+	}
+	var again CreatePreauthCodeRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode CreatePreauthCodeRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("CreatePreauthCodeRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestCreatePreauthCodeResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"pre_auth_code": "PRE_AUTH_CODE", "expires_in": 600}`)
+
+	var got CreatePreauthCodeResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode CreatePreauthCodeResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode CreatePreauthCodeResponse error = %v", err)
+	}
+	var again CreatePreauthCodeResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode CreatePreauthCodeResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("CreatePreauthCodeResponse round-trip got = %v, want %v", again, got)
+	}
+}
+
 func TestCreatePreauthCode(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/CreatePreauthCode.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiCreatePreauthCode, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiCreatePreauthCode)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := CreatePreauthCode(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("CreatePreauthCode() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("CreatePreauthCode() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("CreatePreauthCode() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("CreatePreauthCode() gotResp = %s, want %s", gotResp, tt.wantResp)
+			}
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("CreatePreauthCode() calls = %d, want %d", len(requests), tt.wantCalls)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("CreatePreauthCode() gotResp = %v, want %v", gotResp, tt.wantResp)
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiCreatePreauthCode {
+					t.Errorf("CreatePreauthCode() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiCreatePreauthCode)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("CreatePreauthCode() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("CreatePreauthCode() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"pre_auth_code": "PRE_AUTH_CODE", "expires_in": 600}`)
+		mock.Reset(resp)
+		var want CreatePreauthCodeResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := CreatePreauthCodeTyped(test.MockPlatform, CreatePreauthCodeRequest{})
+		if err != nil {
+			t.Errorf("CreatePreauthCodeTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("CreatePreauthCodeTyped() got = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestApiQueryAuthRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "authorization_code": "AUTH_CODE"}`)
+
+	var got ApiQueryAuthRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiQueryAuthRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiQueryAuthRequest error = %v", err)
+	}
+	var again ApiQueryAuthRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiQueryAuthRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiQueryAuthRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiQueryAuthResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"authorization_info": {"authorizer_appid": "AUTHORIZER_APPID", "authorizer_access_token": "AUTHORIZER_ACCESS_TOKEN", "expires_in": 7200, "authorizer_refresh_token": "REFRESH_TOKEN", "func_info": [{"funcscope_category": {"id": 1}}, {"funcscope_category": {"id": 2}}]}}`)
+
+	var got ApiQueryAuthResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiQueryAuthResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiQueryAuthResponse error = %v", err)
+	}
+	var again ApiQueryAuthResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiQueryAuthResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiQueryAuthResponse round-trip got = %v, want %v", again, got)
+	}
 }
 
 func TestApiQueryAuth(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/ApiQueryAuth.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiApiQueryAuth, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiApiQueryAuth)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := ApiQueryAuth(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApiQueryAuth() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiQueryAuth() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiQueryAuth() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiQueryAuth() gotResp = %s, want %s", gotResp, tt.wantResp)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("ApiQueryAuth() gotResp = %v, want %v", gotResp, tt.wantResp)
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiQueryAuth() calls = %d, want %d", len(requests), tt.wantCalls)
+			}
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiQueryAuth {
+					t.Errorf("ApiQueryAuth() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiQueryAuth)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiQueryAuth() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiQueryAuth() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"authorization_info": {"authorizer_appid": "AUTHORIZER_APPID", "authorizer_access_token": "AUTHORIZER_ACCESS_TOKEN", "expires_in": 7200, "authorizer_refresh_token": "REFRESH_TOKEN", "func_info": [{"funcscope_category": {"id": 1}}, {"funcscope_category": {"id": 2}}]}}`)
+		mock.Reset(resp)
+		var want ApiQueryAuthResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := ApiQueryAuthTyped(test.MockPlatform, ApiQueryAuthRequest{})
+		if err != nil {
+			t.Errorf("ApiQueryAuthTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ApiQueryAuthTyped() got = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestApiAuthorizerTokenRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "authorizer_refresh_token": "REFRESH_TOKEN"}`)
+
+	var got ApiAuthorizerTokenRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiAuthorizerTokenRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiAuthorizerTokenRequest error = %v", err)
+	}
+	var again ApiAuthorizerTokenRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiAuthorizerTokenRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiAuthorizerTokenRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiAuthorizerTokenResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"authorizer_access_token": "AUTHORIZER_ACCESS_TOKEN", "expires_in": 7200, "authorizer_refresh_token": "REFRESH_TOKEN"}`)
+
+	var got ApiAuthorizerTokenResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiAuthorizerTokenResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiAuthorizerTokenResponse error = %v", err)
+	}
+	var again ApiAuthorizerTokenResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiAuthorizerTokenResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiAuthorizerTokenResponse round-trip got = %v, want %v", again, got)
+	}
 }
+
 func TestApiAuthorizerToken(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/ApiAuthorizerToken.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiApiAuthorizerToken, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiApiAuthorizerToken)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := ApiAuthorizerToken(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApiAuthorizerToken() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiAuthorizerToken() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiAuthorizerToken() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiAuthorizerToken() gotResp = %s, want %s", gotResp, tt.wantResp)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("ApiAuthorizerToken() gotResp = %v, want %v", gotResp, tt.wantResp)
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiAuthorizerToken() calls = %d, want %d", len(requests), tt.wantCalls)
+			}
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiAuthorizerToken {
+					t.Errorf("ApiAuthorizerToken() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiAuthorizerToken)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiAuthorizerToken() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiAuthorizerToken() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"authorizer_access_token": "AUTHORIZER_ACCESS_TOKEN", "expires_in": 7200, "authorizer_refresh_token": "REFRESH_TOKEN"}`)
+		mock.Reset(resp)
+		var want ApiAuthorizerTokenResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := ApiAuthorizerTokenTyped(test.MockPlatform, ApiAuthorizerTokenRequest{})
+		if err != nil {
+			t.Errorf("ApiAuthorizerTokenTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ApiAuthorizerTokenTyped() got = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestApiGetAuthorizerInfoRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID"}`)
+
+	var got ApiGetAuthorizerInfoRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerInfoRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerInfoRequest error = %v", err)
+	}
+	var again ApiGetAuthorizerInfoRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerInfoRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerInfoRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiGetAuthorizerInfoResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"authorizer_info": {"nick_name": "微信SDK Demo Special", "head_img": "http://wx.qlogo.cn/mmopen/GPy", "service_type_info": {"id": 2}, "verify_type_info": {"id": 0}, "user_name": "gh_eb5e3a772040", "principal_name": "腾讯计算机系统有限公司", "alias": "paytest01", "qrcode_url": "URL", "signature": "SIGNATURE"}, "authorization_info": {"authorizer_appid": "AUTHORIZER_APPID", "func_info": [{"funcscope_category": {"id": 1}}]}}`)
+
+	var got ApiGetAuthorizerInfoResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerInfoResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerInfoResponse error = %v", err)
+	}
+	var again ApiGetAuthorizerInfoResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerInfoResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerInfoResponse round-trip got = %v, want %v", again, got)
+	}
 }
+
 func TestApiGetAuthorizerInfo(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/ApiGetAuthorizerInfo.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiApiGetAuthorizerInfo, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiApiGetAuthorizerInfo)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := ApiGetAuthorizerInfo(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApiGetAuthorizerInfo() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiGetAuthorizerInfo() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiGetAuthorizerInfo() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiGetAuthorizerInfo() gotResp = %s, want %s", gotResp, tt.wantResp)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("ApiGetAuthorizerInfo() gotResp = %v, want %v", gotResp, tt.wantResp)
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiGetAuthorizerInfo() calls = %d, want %d", len(requests), tt.wantCalls)
+			}
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiGetAuthorizerInfo {
+					t.Errorf("ApiGetAuthorizerInfo() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiGetAuthorizerInfo)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiGetAuthorizerInfo() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiGetAuthorizerInfo() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"authorizer_info": {"nick_name": "微信SDK Demo Special", "head_img": "http://wx.qlogo.cn/mmopen/GPy", "service_type_info": {"id": 2}, "verify_type_info": {"id": 0}, "user_name": "gh_eb5e3a772040", "principal_name": "腾讯计算机系统有限公司", "alias": "paytest01", "qrcode_url": "URL", "signature": "SIGNATURE"}, "authorization_info": {"authorizer_appid": "AUTHORIZER_APPID", "func_info": [{"funcscope_category": {"id": 1}}]}}`)
+		mock.Reset(resp)
+		var want ApiGetAuthorizerInfoResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := ApiGetAuthorizerInfoTyped(test.MockPlatform, ApiGetAuthorizerInfoRequest{})
+		if err != nil {
+			t.Errorf("ApiGetAuthorizerInfoTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ApiGetAuthorizerInfoTyped() got = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestApiGetAuthorizerOptionRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize"}`)
+
+	var got ApiGetAuthorizerOptionRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerOptionRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerOptionRequest error = %v", err)
+	}
+	var again ApiGetAuthorizerOptionRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerOptionRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerOptionRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiGetAuthorizerOptionResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize", "option_value": "1"}`)
+
+	var got ApiGetAuthorizerOptionResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerOptionResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerOptionResponse error = %v", err)
+	}
+	var again ApiGetAuthorizerOptionResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerOptionResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerOptionResponse round-trip got = %v, want %v", again, got)
+	}
 }
+
 func TestApiGetAuthorizerOption(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/ApiGetAuthorizerOption.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiApiGetAuthorizerOption, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiApiGetAuthorizerOption)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := ApiGetAuthorizerOption(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApiGetAuthorizerOption() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiGetAuthorizerOption() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiGetAuthorizerOption() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiGetAuthorizerOption() gotResp = %s, want %s", gotResp, tt.wantResp)
+			}
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiGetAuthorizerOption() calls = %d, want %d", len(requests), tt.wantCalls)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("ApiGetAuthorizerOption() gotResp = %v, want %v", gotResp, tt.wantResp)
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiGetAuthorizerOption {
+					t.Errorf("ApiGetAuthorizerOption() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiGetAuthorizerOption)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiGetAuthorizerOption() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiGetAuthorizerOption() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize", "option_value": "1"}`)
+		mock.Reset(resp)
+		var want ApiGetAuthorizerOptionResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := ApiGetAuthorizerOptionTyped(test.MockPlatform, ApiGetAuthorizerOptionRequest{})
+		if err != nil {
+			t.Errorf("ApiGetAuthorizerOptionTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ApiGetAuthorizerOptionTyped() got = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestApiSetAuthorizerOptionRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize", "option_value": "1"}`)
+
+	var got ApiSetAuthorizerOptionRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiSetAuthorizerOptionRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiSetAuthorizerOptionRequest error = %v", err)
+	}
+	var again ApiSetAuthorizerOptionRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiSetAuthorizerOptionRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiSetAuthorizerOptionRequest round-trip got = %v, want %v", again, got)
+	}
 }
+
 func TestApiSetAuthorizerOption(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/ApiSetAuthorizerOption.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(apiApiSetAuthorizerOption, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(apiApiSetAuthorizerOption)
 
 	type args struct {
 		ctx     *wxopen.Platform
 		payload []byte
 	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := ApiSetAuthorizerOption(tt.args.ctx, tt.args.payload)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ApiSetAuthorizerOption() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiSetAuthorizerOption() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiSetAuthorizerOption() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiSetAuthorizerOption() gotResp = %s, want %s", gotResp, tt.wantResp)
+			}
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiSetAuthorizerOption() calls = %d, want %d", len(requests), tt.wantCalls)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("ApiSetAuthorizerOption() gotResp = %v, want %v", gotResp, tt.wantResp)
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiSetAuthorizerOption {
+					t.Errorf("ApiSetAuthorizerOption() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiSetAuthorizerOption)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiSetAuthorizerOption() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiSetAuthorizerOption() body = %s, want %s", req.Body, fixture.Body)
+				}
 			}
 		})
 	}
 }
+
+func TestApiGetAuthorizerListRequestRoundTrip(t *testing.T) {
+	sample := []byte(`{"component_appid": "COMPONENT_APPID", "offset": 0, "count": 100}`)
+
+	var got ApiGetAuthorizerListRequest
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerListRequest error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerListRequest error = %v", err)
+	}
+	var again ApiGetAuthorizerListRequest
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerListRequest error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerListRequest round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiGetAuthorizerListResponseRoundTrip(t *testing.T) {
+	sample := []byte(`{"total_count": 1, "list": [{"authorizer_appid": "AUTHORIZER_APPID", "refresh_token": "REFRESH_TOKEN", "auth_time": 1558000607}]}`)
+
+	var got ApiGetAuthorizerListResponse
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerListResponse error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode ApiGetAuthorizerListResponse error = %v", err)
+	}
+	var again ApiGetAuthorizerListResponse
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode ApiGetAuthorizerListResponse error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("ApiGetAuthorizerListResponse round-trip got = %v, want %v", again, got)
+	}
+}
+
+func TestApiGetAuthorizerList(t *testing.T) {
+	fixture, err := test.LoadFixture("testdata/ApiGetAuthorizerList.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	mock := test.HandleMockApi(apiApiGetAuthorizerList)
+
+	type args struct {
+		ctx     *wxopen.Platform
+		payload []byte
+	}
+	arguments := args{ctx: test.MockPlatform, payload: []byte(fixture.Body)}
+	tests := []struct {
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
+	}{
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.Reset(tt.responses...)
+			gotResp, err := ApiGetAuthorizerList(tt.args.ctx, tt.args.payload)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApiGetAuthorizerList() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("ApiGetAuthorizerList() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("ApiGetAuthorizerList() gotResp = %s, want %s", gotResp, tt.wantResp)
+			}
+
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("ApiGetAuthorizerList() calls = %d, want %d", len(requests), tt.wantCalls)
+			}
+			for _, req := range requests {
+				if req.Method != http.MethodPost || req.Path != apiApiGetAuthorizerList {
+					t.Errorf("ApiGetAuthorizerList() request = %s %s, want %s %s", req.Method, req.Path, http.MethodPost, apiApiGetAuthorizerList)
+				}
+				if got := req.Query.Get("component_access_token"); got != "ACCESS_TOKEN" {
+					t.Errorf("ApiGetAuthorizerList() component_access_token = %q, want %q", got, "ACCESS_TOKEN")
+				}
+				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("ApiGetAuthorizerList() body = %s, want %s", req.Body, fixture.Body)
+				}
+			}
+		})
+	}
+
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(`{"total_count": 1, "list": [{"authorizer_appid": "AUTHORIZER_APPID", "refresh_token": "REFRESH_TOKEN", "auth_time": 1558000607}]}`)
+		mock.Reset(resp)
+		var want ApiGetAuthorizerListResponse
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := ApiGetAuthorizerListTyped(test.MockPlatform, ApiGetAuthorizerListRequest{})
+		if err != nil {
+			t.Errorf("ApiGetAuthorizerListTyped() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ApiGetAuthorizerListTyped() got = %v, want %v", got, want)
+		}
+	})
+}
@@ -12,65 +12,132 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by cmd from apis.json. DO NOT EDIT.
+
 package auth_test
 
 import (
 	"fmt"
 
-	"github.com/fastwego/wxopen"
 	"github.com/fastwego/wxopen/apis/auth"
+	"github.com/fastwego/wxopen/test"
 )
 
 func ExampleCreatePreauthCode() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.CreatePreauthCode(ctx, payload)
-
-	fmt.Println(resp, err)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_create_preauthcode").Reset([]byte(`{"pre_auth_code":"PRE_AUTH_CODE","expires_in":600}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID"}`)
+	resp, err := auth.CreatePreauthCode(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"pre_auth_code":"PRE_AUTH_CODE","expires_in":600}
 }
 
 func ExampleApiQueryAuth() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.ApiQueryAuth(ctx, payload)
-
-	fmt.Println(resp, err)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_query_auth").Reset([]byte(`{"authorization_info":{"authorizer_appid":"AUTHORIZER_APPID","authorizer_access_token":"AUTHORIZER_ACCESS_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN","func_info":[{"funcscope_category":{"id":1}},{"funcscope_category":{"id":2}}]}}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "authorization_code": "AUTH_CODE"}`)
+	resp, err := auth.ApiQueryAuth(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"authorization_info":{"authorizer_appid":"AUTHORIZER_APPID","authorizer_access_token":"AUTHORIZER_ACCESS_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN","func_info":[{"funcscope_category":{"id":1}},{"funcscope_category":{"id":2}}]}}
 }
 
 func ExampleApiAuthorizerToken() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.ApiAuthorizerToken(ctx, payload)
-
-	fmt.Println(resp, err)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_authorizer_token").Reset([]byte(`{"authorizer_access_token":"AUTHORIZER_ACCESS_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "authorizer_refresh_token": "REFRESH_TOKEN"}`)
+	resp, err := auth.ApiAuthorizerToken(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"authorizer_access_token":"AUTHORIZER_ACCESS_TOKEN","expires_in":7200,"authorizer_refresh_token":"REFRESH_TOKEN"}
 }
 
 func ExampleApiGetAuthorizerInfo() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.ApiGetAuthorizerInfo(ctx, payload)
-
-	fmt.Println(resp, err)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_get_authorizer_info").Reset([]byte(`{"authorizer_info":{"nick_name":"微信SDK Demo Special","head_img":"http://wx.qlogo.cn/mmopen/GPy","service_type_info":{"id":2},"verify_type_info":{"id":0},"user_name":"gh_eb5e3a772040","principal_name":"腾讯计算机系统有限公司","alias":"paytest01","qrcode_url":"URL","signature":"SIGNATURE"},"authorization_info":{"authorizer_appid":"AUTHORIZER_APPID","func_info":[{"funcscope_category":{"id":1}}]}}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID"}`)
+	resp, err := auth.ApiGetAuthorizerInfo(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"authorizer_info":{"nick_name":"微信SDK Demo Special","head_img":"http://wx.qlogo.cn/mmopen/GPy","service_type_info":{"id":2},"verify_type_info":{"id":0},"user_name":"gh_eb5e3a772040","principal_name":"腾讯计算机系统有限公司","alias":"paytest01","qrcode_url":"URL","signature":"SIGNATURE"},"authorization_info":{"authorizer_appid":"AUTHORIZER_APPID","func_info":[{"funcscope_category":{"id":1}}]}}
 }
 
 func ExampleApiGetAuthorizerOption() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.ApiGetAuthorizerOption(ctx, payload)
-
-	fmt.Println(resp, err)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_get_authorizer_option").Reset([]byte(`{"authorizer_appid":"AUTHORIZER_APPID","option_name":"voice_recognize","option_value":"1"}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize"}`)
+	resp, err := auth.ApiGetAuthorizerOption(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"authorizer_appid":"AUTHORIZER_APPID","option_name":"voice_recognize","option_value":"1"}
 }
 
 func ExampleApiSetAuthorizerOption() {
-	var ctx *wxopen.Platform
-
-	payload := []byte("{}")
-	resp, err := auth.ApiSetAuthorizerOption(ctx, payload)
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_set_authorizer_option").Reset([]byte(`{"errcode":0,"errmsg":"ok"}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "authorizer_appid": "AUTHORIZER_APPID", "option_name": "voice_recognize", "option_value": "1"}`)
+	resp, err := auth.ApiSetAuthorizerOption(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"errcode":0,"errmsg":"ok"}
+}
 
-	fmt.Println(resp, err)
+func ExampleApiGetAuthorizerList() {
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("/cgi-bin/component/api_get_authorizer_list").Reset([]byte(`{"total_count":1,"list":[{"authorizer_appid":"AUTHORIZER_APPID","refresh_token":"REFRESH_TOKEN","auth_time":1558000607}]}`))
+
+	payload := []byte(`{"component_appid": "COMPONENT_APPID", "offset": 0, "count": 100}`)
+	resp, err := auth.ApiGetAuthorizerList(test.MockPlatform, payload)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s\n", resp)
+
+	// Output:
+	// {"total_count":1,"list":[{"authorizer_appid":"AUTHORIZER_APPID","refresh_token":"REFRESH_TOKEN","auth_time":1558000607}]}
 }
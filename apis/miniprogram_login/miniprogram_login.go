@@ -0,0 +1,177 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package miniprogram_login 代小程序实现登录
+package miniprogram_login
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/fastwego/wxopen"
+)
+
+const (
+	apiJsCode2Session = "/sns/component/jscode2session"
+)
+
+var ErrorWatermarkAppidMismatch = errors.New("watermark appid mismatch")
+
+type Session struct {
+	Openid     string `json:"openid"`
+	SessionKey string `json:"session_key"`
+	Unionid    string `json:"unionid"`
+}
+
+/*
+小程序登录
+
+第三方平台开发者的服务器使用登录凭证（code）以及第三方平台的 component_access_token 可以代替小程序实现登录功能，获取 session_key 和 openid
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Mini_Programs/WeChat_login.html
+
+GET https://api.weixin.qq.com/sns/component/jscode2session?appid=APPID&js_code=JSCODE&grant_type=authorization_code&component_appid=COMPONENT_APPID&component_access_token=ACCESS_TOKEN
+*/
+func JsCode2Session(ctx *wxopen.Platform, appid string, jsCode string) (session Session, err error) {
+	params := url.Values{}
+	params.Add("appid", appid)
+	params.Add("js_code", jsCode)
+	params.Add("grant_type", "authorization_code")
+	params.Add("component_appid", ctx.Config.AppId)
+
+	resp, err := ctx.Client.HTTPGet(apiJsCode2Session + "?" + params.Encode())
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &session)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Watermark 敏感数据水印
+type Watermark struct {
+	Appid     string `json:"appid"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type PhoneNumber struct {
+	PhoneNumber     string    `json:"phoneNumber"`
+	PurePhoneNumber string    `json:"purePhoneNumber"`
+	CountryCode     string    `json:"countryCode"`
+	Watermark       Watermark `json:"watermark"`
+}
+
+type UserInfo struct {
+	OpenId    string    `json:"openId"`
+	NickName  string    `json:"nickName"`
+	Gender    int       `json:"gender"`
+	City      string    `json:"city"`
+	Province  string    `json:"province"`
+	Country   string    `json:"country"`
+	AvatarUrl string    `json:"avatarUrl"`
+	UnionId   string    `json:"unionId"`
+	Watermark Watermark `json:"watermark"`
+}
+
+/*
+解密开放数据
+
+使用 session_key 对小程序 wx.getUserInfo/getPhoneNumber 等接口返回的 encryptedData 进行 AES-128-CBC 解密，并校验水印中的 appid
+
+水印中的 appid 不一致时返回 ErrorWatermarkAppidMismatch，data 为 nil
+
+See: https://developers.weixin.qq.com/miniprogram/dev/framework/open-ability/signature.html
+*/
+func DecryptData(appid string, sessionKey string, encryptedData string, iv string) (data []byte, err error) {
+	key, err := base64.StdEncoding.DecodeString(sessionKey)
+	if err != nil {
+		return
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil {
+		return
+	}
+	ivBytes, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	if len(ivBytes) != block.BlockSize() {
+		err = fmt.Errorf("the length of iv incorrect: %d", len(ivBytes))
+		return
+	}
+	if len(cipherText) == 0 || len(cipherText)%block.BlockSize() != 0 {
+		err = fmt.Errorf("the length of ciphertext incorrect: %d", len(cipherText))
+		return
+	}
+
+	plaintext := make([]byte, len(cipherText))
+	cipher.NewCBCDecrypter(block, ivBytes).CryptBlocks(plaintext, cipherText)
+
+	// PKCS#7 去除补位
+	amountToPad := int(plaintext[len(plaintext)-1])
+	if amountToPad < 1 || amountToPad > block.BlockSize() || !bytes.HasSuffix(plaintext, bytes.Repeat([]byte{byte(amountToPad)}, amountToPad)) {
+		err = fmt.Errorf("the amount to pad is incorrect: %d", amountToPad)
+		return
+	}
+	data = plaintext[:len(plaintext)-amountToPad]
+
+	watermark := struct {
+		Watermark Watermark `json:"watermark"`
+	}{}
+	err = json.Unmarshal(data, &watermark)
+	if err != nil {
+		return nil, err
+	}
+	// 水印校验失败的数据不可信，不返回
+	if watermark.Watermark.Appid != appid {
+		return nil, ErrorWatermarkAppidMismatch
+	}
+	return
+}
+
+// DecryptPhoneNumber 解密手机号
+func DecryptPhoneNumber(appid string, sessionKey string, encryptedData string, iv string) (phoneNumber PhoneNumber, err error) {
+	data, err := DecryptData(appid, sessionKey, encryptedData, iv)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &phoneNumber)
+	return
+}
+
+// DecryptUserInfo 解密用户信息
+func DecryptUserInfo(appid string, sessionKey string, encryptedData string, iv string) (userInfo UserInfo, err error) {
+	data, err := DecryptData(appid, sessionKey, encryptedData, iv)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &userInfo)
+	return
+}
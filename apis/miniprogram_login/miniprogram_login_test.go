@@ -0,0 +1,145 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package miniprogram_login
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestJsCode2Session(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiJsCode2Session, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "AUTHORIZER_APPID" || q.Get("js_code") != "CODE" || q.Get("grant_type") != "authorization_code" ||
+			q.Get("component_appid") != "APPID" || q.Get("component_access_token") != "ACCESS_TOKEN" {
+			_, _ = w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"openid":"OPENID","session_key":"SESSIONKEY","unionid":"UNIONID"}`))
+	})
+
+	tests := []struct {
+		name        string
+		jsCode      string
+		wantSession Session
+		wantErr     bool
+	}{
+		{name: "case1", jsCode: "CODE", wantSession: Session{Openid: "OPENID", SessionKey: "SESSIONKEY", Unionid: "UNIONID"}},
+		{name: "invalid code", jsCode: "INVALID", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSession, err := JsCode2Session(test.MockPlatform, "AUTHORIZER_APPID", tt.jsCode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JsCode2Session() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotSession, tt.wantSession) {
+				t.Errorf("JsCode2Session() gotSession = %v, want %v", gotSession, tt.wantSession)
+			}
+		})
+	}
+}
+
+var (
+	key        = []byte("0123456789abcdef")
+	iv         = []byte("fedcba9876543210")
+	sessionKey = base64.StdEncoding.EncodeToString(key)
+	encodedIv  = base64.StdEncoding.EncodeToString(iv)
+)
+
+// encrypt 模拟微信加密开放数据
+func encrypt(plaintext string) string {
+	block, _ := aes.NewCipher(key)
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	data := append([]byte(plaintext), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestDecryptData(t *testing.T) {
+	plaintext := `{"openId":"OPENID","watermark":{"appid":"AUTHORIZER_APPID","timestamp":1477314187}}`
+
+	tests := []struct {
+		name          string
+		appid         string
+		encryptedData string
+		want          []byte
+		wantErr       error
+	}{
+		{name: "valid", appid: "AUTHORIZER_APPID", encryptedData: encrypt(plaintext), want: []byte(plaintext)},
+		{name: "watermark mismatch", appid: "OTHER_APPID", encryptedData: encrypt(plaintext), wantErr: ErrorWatermarkAppidMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptData(tt.appid, sessionKey, tt.encryptedData, encodedIv)
+			if err != tt.wantErr {
+				t.Errorf("DecryptData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("DecryptData() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 非 json 数据无法校验水印
+	if got, err := DecryptData("AUTHORIZER_APPID", sessionKey, encrypt("not json"), encodedIv); err == nil || got != nil {
+		t.Errorf("DecryptData() got = %s, error = %v, want nil data and error", got, err)
+	}
+}
+
+func TestDecryptPhoneNumber(t *testing.T) {
+	encryptedData := encrypt(`{"phoneNumber":"+86 13800138000","purePhoneNumber":"13800138000","countryCode":"86","watermark":{"appid":"AUTHORIZER_APPID","timestamp":1477314187}}`)
+
+	tests := []struct {
+		name          string
+		appid         string
+		encryptedData string
+		want          PhoneNumber
+		wantErr       bool
+	}{
+		{
+			name: "case1", appid: "AUTHORIZER_APPID", encryptedData: encryptedData,
+			want: PhoneNumber{PhoneNumber: "+86 13800138000", PurePhoneNumber: "13800138000", CountryCode: "86", Watermark: Watermark{Appid: "AUTHORIZER_APPID", Timestamp: 1477314187}},
+		},
+		{name: "watermark mismatch", appid: "OTHER_APPID", encryptedData: encryptedData, wantErr: true},
+		{name: "invalid data", appid: "AUTHORIZER_APPID", encryptedData: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptPhoneNumber(tt.appid, sessionKey, tt.encryptedData, encodedIv)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecryptPhoneNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecryptPhoneNumber() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
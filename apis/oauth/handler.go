@@ -0,0 +1,272 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastwego/wxopen"
+)
+
+const (
+	ScopeBase     = "snsapi_base"     // 静默授权，只能获取 openid
+	ScopeUserInfo = "snsapi_userinfo" // 弹出授权页面，可获取用户信息
+
+	stateCookieName = "wxopen_oauth_state"
+
+	stateAppidLen     = 18 // 公众号 appid 长度
+	stateTimestampLen = 10 // unix 时间戳长度
+)
+
+// state 只能包含字母和数字，appid 定长，不需要分隔符
+var stateAppidPattern = regexp.MustCompile(`^[a-zA-Z0-9]{18}$`)
+
+var (
+	ErrorInvalidScope    = errors.New("invalid scope")
+	ErrorInvalidAppid    = errors.New("invalid appid")
+	ErrorInvalidState    = errors.New("invalid state")
+	ErrorStateExpired    = errors.New("state expired")
+	ErrorAuthorizeDenied = errors.New("authorize denied")
+)
+
+// Identity 网页授权获得的用户身份
+type Identity struct {
+	Appid       string      // 授权方公众号 appid
+	AccessToken AccessToken // 网页授权 access_token
+	UserInfo    *UserInfo   // scope 为 snsapi_userinfo 时拉取的用户信息
+}
+
+// IdentityFunc 网页授权成功回调方法
+type IdentityFunc func(writer http.ResponseWriter, request *http.Request, identity Identity)
+
+// ErrorFunc 网页授权失败回调方法
+type ErrorFunc func(writer http.ResponseWriter, request *http.Request, err error)
+
+/*
+HandlerConfig 网页授权处理器配置
+*/
+type HandlerConfig struct {
+	Appid       string        // 授权方公众号 appid；为空时从请求参数 appid 中获取
+	Scope       string        // 授权作用域 snsapi_base/snsapi_userinfo
+	RedirectUri string        // 网页授权回调地址，即本处理器的完整 URL
+	StateSecret string        // 签名 state 的密钥
+	StateExpire time.Duration // state 有效期，默认 10 分钟
+}
+
+/*
+Handler 代公众号发起网页授权的 http.Handler
+
+- 请求不带 state 参数时，生成签名 state，并跳转至授权页面
+
+- 微信回调时，校验 state，使用 code 换取 access_token，scope 为 snsapi_userinfo 时拉取用户信息，最后交由 IdentityHandler 处理
+
+设置 UserTokens 后，换取的用户令牌会被保存，后续可通过 UserTokens 自动刷新使用
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Official_Accounts/official_account_website_authorization.html
+*/
+type Handler struct {
+	Ctx    *wxopen.Platform
+	Config HandlerConfig
+
+	IdentityHandler IdentityFunc
+	ErrorHandler    ErrorFunc
+
+	UserTokens *UserTokenManager
+}
+
+/*
+创建网页授权处理器
+*/
+func NewHandler(ctx *wxopen.Platform, config HandlerConfig, identityHandler IdentityFunc) (handler *Handler, err error) {
+	if config.Scope != ScopeBase && config.Scope != ScopeUserInfo {
+		return nil, ErrorInvalidScope
+	}
+	if config.StateSecret == "" {
+		return nil, errors.New("state secret required")
+	}
+	if config.StateExpire == 0 {
+		config.StateExpire = 10 * time.Minute
+	}
+
+	return &Handler{
+		Ctx:             ctx,
+		Config:          config,
+		IdentityHandler: identityHandler,
+		ErrorHandler:    DefaultErrorHandler,
+	}, nil
+}
+
+// DefaultErrorHandler 默认网页授权失败响应
+func DefaultErrorHandler(writer http.ResponseWriter, request *http.Request, err error) {
+	http.Error(writer, err.Error(), http.StatusForbidden)
+}
+
+// ServeHTTP 发起网页授权或处理授权回调
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Query().Get("state") == "" {
+		h.authorize(writer, request)
+		return
+	}
+
+	identity, err := h.callback(request)
+	if err != nil {
+		h.ErrorHandler(writer, request, err)
+		return
+	}
+
+	// state 只能使用一次
+	http.SetCookie(writer, &http.Cookie{Name: stateCookieName, Path: "/", MaxAge: -1})
+
+	h.IdentityHandler(writer, request, identity)
+}
+
+// authorize 生成 state 并跳转至授权页面
+func (h *Handler) authorize(writer http.ResponseWriter, request *http.Request) {
+	appid := h.Config.Appid
+	if appid == "" {
+		appid = request.URL.Query().Get("appid")
+	}
+	if appid == "" {
+		h.ErrorHandler(writer, request, wxopen.ErrorAuthorizerAppidNotFound)
+		return
+	}
+
+	if !stateAppidPattern.MatchString(appid) {
+		h.ErrorHandler(writer, request, ErrorInvalidAppid)
+		return
+	}
+
+	// nonce 写入 cookie，回调时与 state 比对，防止 CSRF；须使用 crypto/rand 生成，不可预测
+	nonce, err := randomNonce()
+	if err != nil {
+		h.ErrorHandler(writer, request, err)
+		return
+	}
+	state := h.signState(appid, fmt.Sprintf("%0*d", stateTimestampLen, time.Now().Unix()), nonce)
+	http.SetCookie(writer, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    nonce,
+		Path:     "/",
+		MaxAge:   int(h.Config.StateExpire / time.Second),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	redirectUri, err := GetAuthorizeUrl(h.Ctx, appid, h.Config.RedirectUri, h.Config.Scope, state)
+	if err != nil {
+		h.ErrorHandler(writer, request, err)
+		return
+	}
+	http.Redirect(writer, request, redirectUri, http.StatusFound)
+}
+
+// callback 校验 state，换取 access_token 与用户信息
+func (h *Handler) callback(request *http.Request) (identity Identity, err error) {
+	query := request.URL.Query()
+
+	appid, err := h.verifyState(request, query.Get("state"))
+	if err != nil {
+		return
+	}
+
+	// 用户拒绝授权时不会带上 code
+	code := query.Get("code")
+	if code == "" {
+		err = ErrorAuthorizeDenied
+		return
+	}
+
+	accessToken, err := GetAccessToken(h.Ctx, appid, code)
+	if err != nil {
+		return
+	}
+	identity = Identity{Appid: appid, AccessToken: accessToken}
+
+	if h.UserTokens != nil {
+		_, err = h.UserTokens.Save(appid, accessToken)
+		if err != nil {
+			return
+		}
+	}
+
+	if h.Config.Scope == ScopeUserInfo && strings.Contains(accessToken.Scope, ScopeUserInfo) {
+		var userInfo UserInfo
+		userInfo, err = GetUserInfo(h.Ctx, accessToken.AccessToken, accessToken.Openid)
+		if err != nil {
+			return
+		}
+		identity.UserInfo = &userInfo
+	}
+	return
+}
+
+// randomNonce 生成 16 字节随机数的 hex 编码
+func randomNonce() (nonce string, err error) {
+	b := make([]byte, 16)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	return hex.EncodeToString(b), nil
+}
+
+/*
+signState 生成 state
+
+格式为 appid(18 位) + unix 时间戳(10 位) + hex(HMAC-SHA256(appid + 时间戳 + nonce))，共 92 字节
+
+微信要求 state 只包含字母和数字，不超过 128 字节
+*/
+func (h *Handler) signState(appid string, timestamp string, nonce string) (state string) {
+	mac := hmac.New(sha256.New, []byte(h.Config.StateSecret))
+	_, _ = mac.Write([]byte(appid + timestamp + nonce))
+	return appid + timestamp + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyState 校验 state 签名及有效期，返回授权方 appid
+func (h *Handler) verifyState(request *http.Request, state string) (appid string, err error) {
+	if len(state) != stateAppidLen+stateTimestampLen+sha256.Size*2 {
+		return "", ErrorInvalidState
+	}
+	appid, timestampStr := state[:stateAppidLen], state[stateAppidLen:stateAppidLen+stateTimestampLen]
+
+	cookie, err := request.Cookie(stateCookieName)
+	if err != nil {
+		return "", ErrorInvalidState
+	}
+
+	if !hmac.Equal([]byte(state), []byte(h.signState(appid, timestampStr, cookie.Value))) {
+		return "", ErrorInvalidState
+	}
+
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return "", ErrorInvalidState
+	}
+	if time.Since(time.Unix(timestamp, 0)) > h.Config.StateExpire {
+		return "", ErrorStateExpired
+	}
+
+	return appid, nil
+}
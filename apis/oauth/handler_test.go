@@ -0,0 +1,145 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestHandler(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiGetAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code") != "CODE" {
+			_, _ = w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"USER_ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_userinfo"}`))
+	})
+	test.MockSvrHandler.HandleFunc(apiGetUserInfo, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"openid":"OPENID","nickname":"NICKNAME","unionid":"UNIONID"}`))
+	})
+
+	var gotIdentity Identity
+	handler, err := NewHandler(test.MockPlatform, HandlerConfig{
+		Appid:       "wx570bc396a51b8ff8",
+		Scope:       ScopeUserInfo,
+		RedirectUri: "https://example.com/oauth",
+		StateSecret: "SECRET",
+	}, func(writer http.ResponseWriter, request *http.Request, identity Identity) {
+		gotIdentity = identity
+	})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	// 发起授权
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/oauth", nil))
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("authorize status = %d, want %d", recorder.Code, http.StatusFound)
+	}
+	location, _ := url.Parse(recorder.Header().Get("Location"))
+	if location.Host != "open.weixin.qq.com" || location.Query().Get("component_appid") != "APPID" || location.Query().Get("scope") != ScopeUserInfo {
+		t.Fatalf("authorize location = %s", location)
+	}
+	state := location.Query().Get("state")
+	if !regexp.MustCompile(`^[a-zA-Z0-9]{1,128}$`).MatchString(state) {
+		t.Fatalf("authorize state = %q, want [a-zA-Z0-9]{1,128}", state)
+	}
+	cookie := recorder.Result().Cookies()[0]
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(cookie.Value) {
+		t.Fatalf("authorize nonce = %q, want 16 random bytes in hex", cookie.Value)
+	}
+	again := httptest.NewRecorder()
+	handler.ServeHTTP(again, httptest.NewRequest(http.MethodGet, "/oauth", nil))
+	if again.Result().Cookies()[0].Value == cookie.Value {
+		t.Fatalf("authorize nonce repeated: %q", cookie.Value)
+	}
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		cookie  *http.Cookie
+		want    Identity
+		wantErr bool
+	}{
+		{
+			name:   "case1",
+			query:  url.Values{"code": {"CODE"}, "state": {state}},
+			cookie: cookie,
+			want: Identity{
+				Appid:       "wx570bc396a51b8ff8",
+				AccessToken: AccessToken{AccessToken: "USER_ACCESS_TOKEN", ExpiresIn: 7200, RefreshToken: "REFRESH_TOKEN", Openid: "OPENID", Scope: ScopeUserInfo},
+				UserInfo:    &UserInfo{Openid: "OPENID", Nickname: "NICKNAME", Unionid: "UNIONID"},
+			},
+		},
+		{name: "tampered state", query: url.Values{"code": {"CODE"}, "state": {"wxd101a85aa106f53e" + state[len("wx570bc396a51b8ff8"):]}}, cookie: cookie, wantErr: true},
+		{name: "truncated state", query: url.Values{"code": {"CODE"}, "state": {state[:len(state)-1]}}, cookie: cookie, wantErr: true},
+		{name: "missing cookie", query: url.Values{"code": {"CODE"}, "state": {state}}, wantErr: true},
+		{name: "denied", query: url.Values{"state": {state}}, cookie: cookie, wantErr: true},
+		{name: "invalid code", query: url.Values{"code": {"INVALID"}, "state": {state}}, cookie: cookie, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIdentity = Identity{}
+			request := httptest.NewRequest(http.MethodGet, "/oauth?"+tt.query.Encode(), nil)
+			if tt.cookie != nil {
+				request.AddCookie(tt.cookie)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if (recorder.Code == http.StatusForbidden) != tt.wantErr {
+				t.Errorf("callback status = %d, wantErr %v, body %s", recorder.Code, tt.wantErr, recorder.Body)
+				return
+			}
+			if !reflect.DeepEqual(gotIdentity, tt.want) {
+				t.Errorf("callback identity = %+v, want %+v", gotIdentity, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_InvalidAppid(t *testing.T) {
+	handler, err := NewHandler(test.MockPlatform, HandlerConfig{Scope: ScopeBase, StateSecret: "SECRET"}, nil)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/oauth?appid=wx.invalid", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("authorize status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	_, err := NewHandler(test.MockPlatform, HandlerConfig{Scope: "snsapi_login", StateSecret: "SECRET"}, nil)
+	if err != ErrorInvalidScope {
+		t.Errorf("NewHandler() error = %v, want %v", err, ErrorInvalidScope)
+	}
+}
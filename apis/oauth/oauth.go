@@ -41,7 +41,7 @@ GET https://open.weixin.qq.com/connect/oauth2/authorize?appid=APPID&redirect_uri
 */
 func GetAuthorizeUrl(ctx *wxopen.Platform, appid string, redirect_uri string, scope string, state string) (redirectUri string, err error) {
 	uriTpl := "https://open.weixin.qq.com/connect/oauth2/authorize?appid=%s&redirect_uri=%s&response_type=code&scope=%s&state=%s&component_appid=%s#wechat_redirect"
-	redirectUri = fmt.Sprintf(uriTpl, appid, url.QueryEscape(redirect_uri), scope, state, ctx.Config.AppId)
+	redirectUri = fmt.Sprintf(uriTpl, appid, url.QueryEscape(redirect_uri), scope, url.QueryEscape(state), ctx.Config.AppId)
 	return
 }
 
@@ -139,7 +139,8 @@ func GetUserInfo(ctx *wxopen.Platform, access_token string, openid string) (user
 	params.Add("openid", openid)
 	params.Add("lang", "zh_CN")
 
-	resp, err := ctx.Client.HTTPGet(apiGetUserInfo + "?" + params.Encode())
+	// 只使用用户 access_token 鉴权
+	resp, err := ctx.Client.HTTPGetWithAuth(wxopen.AuthUser, apiGetUserInfo+"?"+params.Encode())
 	if err != nil {
 		return
 	}
@@ -0,0 +1,199 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	"github.com/fastwego/wxopen"
+)
+
+// refresh_token 有效期 30 天
+const RefreshTokenLifetime = 30 * 24 * time.Hour
+
+// ErrorReauthorizationRequired 没有可用的用户令牌或 refresh_token 已失效，需要用户重新授权
+var ErrorReauthorizationRequired = errors.New("reauthorization required")
+
+/*
+UserToken 用户网页授权令牌
+*/
+type UserToken struct {
+	AccessToken
+	Appid                string    `json:"appid"`
+	AccessTokenExpireAt  time.Time `json:"access_token_expire_at"`
+	RefreshTokenExpireAt time.Time `json:"refresh_token_expire_at"`
+}
+
+/*
+UserTokenStore 用户网页授权令牌存储接口
+
+Fetch 在令牌不存在时返回 error
+*/
+type UserTokenStore interface {
+	Save(appid string, openid string, token UserToken) (err error)
+	Fetch(appid string, openid string) (token UserToken, err error)
+	Delete(appid string, openid string) (err error)
+}
+
+/*
+CacheUserTokenStore 基于 cachego 的用户令牌存储
+
+令牌在 refresh_token 过期后自动清除
+*/
+type CacheUserTokenStore struct {
+	Cache cachego.Cache
+}
+
+func (store *CacheUserTokenStore) key(appid string, openid string) string {
+	return "oauth_user_token:" + appid + ":" + openid
+}
+
+func (store *CacheUserTokenStore) Save(appid string, openid string, token UserToken) (err error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	return store.Cache.Save(store.key(appid, openid), string(data), time.Until(token.RefreshTokenExpireAt))
+}
+
+func (store *CacheUserTokenStore) Fetch(appid string, openid string) (token UserToken, err error) {
+	data, err := store.Cache.Fetch(store.key(appid, openid))
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(data), &token)
+	return
+}
+
+func (store *CacheUserTokenStore) Delete(appid string, openid string) (err error) {
+	return store.Cache.Delete(store.key(appid, openid))
+}
+
+/*
+UserTokenManager 用户网页授权令牌管理器
+
+保存 GetAccessToken 获得的用户令牌，access_token 过期后在 refresh_token 有效期内自动刷新
+*/
+type UserTokenManager struct {
+	Ctx   *wxopen.Platform
+	Store UserTokenStore
+
+	refreshLock sync.Mutex
+}
+
+/*
+创建用户令牌管理器
+
+store 为 nil 时使用平台 Cache 存储
+*/
+func NewUserTokenManager(ctx *wxopen.Platform, store UserTokenStore) *UserTokenManager {
+	if store == nil {
+		store = &CacheUserTokenStore{Cache: ctx.Cache}
+	}
+	return &UserTokenManager{Ctx: ctx, Store: store}
+}
+
+/*
+Save 保存通过 code 换取的用户令牌
+*/
+func (m *UserTokenManager) Save(appid string, accessToken AccessToken) (token UserToken, err error) {
+	now := time.Now()
+	token = UserToken{
+		AccessToken:          accessToken,
+		Appid:                appid,
+		AccessTokenExpireAt:  accessTokenExpireAt(now, accessToken.ExpiresIn),
+		RefreshTokenExpireAt: now.Add(RefreshTokenLifetime),
+	}
+
+	err = m.Store.Save(appid, accessToken.Openid, token)
+	return
+}
+
+/*
+GetUserToken 获取有效的用户令牌
+
+access_token 过期时使用 refresh_token 刷新；令牌不存在或 refresh_token 失效时返回 ErrorReauthorizationRequired
+
+刷新遇到其他错误（如 45009 接口调用超过限制）时保留令牌并原样返回错误
+*/
+func (m *UserTokenManager) GetUserToken(appid string, openid string) (token UserToken, err error) {
+	token, err = m.Store.Fetch(appid, openid)
+	if err != nil {
+		return token, ErrorReauthorizationRequired
+	}
+	if time.Now().Before(token.AccessTokenExpireAt) {
+		return
+	}
+
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	// 其他 goroutine 可能已经刷新
+	token, err = m.Store.Fetch(appid, openid)
+	if err != nil {
+		return token, ErrorReauthorizationRequired
+	}
+	now := time.Now()
+	if now.Before(token.AccessTokenExpireAt) {
+		return
+	}
+
+	if !now.Before(token.RefreshTokenExpireAt) {
+		_ = m.Store.Delete(appid, openid)
+		return token, ErrorReauthorizationRequired
+	}
+
+	accessToken, err := RefreshAccessToken(m.Ctx, appid, token.RefreshToken)
+	if err != nil {
+		// 只有 refresh_token 无效或过期才需要重新授权，其他错误（如接口调用频率超限）保留令牌原样返回
+		var apiError *wxopen.ApiError
+		if errors.As(err, &apiError) && refreshTokenInvalid[apiError.Errcode] {
+			_ = m.Store.Delete(appid, openid)
+			return token, ErrorReauthorizationRequired
+		}
+		return
+	}
+
+	token.AccessToken = accessToken
+	token.AccessTokenExpireAt = accessTokenExpireAt(now, accessToken.ExpiresIn)
+	err = m.Store.Save(appid, openid, token)
+	return
+}
+
+/*
+GetUserInfo 使用保存的用户令牌拉取用户信息
+*/
+func (m *UserTokenManager) GetUserInfo(appid string, openid string) (userInfo UserInfo, err error) {
+	token, err := m.GetUserToken(appid, openid)
+	if err != nil {
+		return
+	}
+	return GetUserInfo(m.Ctx, token.AccessToken.AccessToken, openid)
+}
+
+// refreshTokenInvalid 表示 refresh_token 已失效的错误码
+var refreshTokenInvalid = map[int64]bool{
+	40030: true, // 不合法的 refresh_token
+	42002: true, // refresh_token 超时
+}
+
+// accessTokenExpireAt 过期时间设置为 0.9 * expiresIn 提供一定冗余
+func accessTokenExpireAt(now time.Time, expiresIn int) time.Time {
+	return now.Add(time.Duration(expiresIn) * time.Second * 9 / 10)
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fastwego/wxopen"
+	"github.com/fastwego/wxopen/test"
+)
+
+type memoryUserTokenStore map[string]UserToken
+
+func (store memoryUserTokenStore) Save(appid string, openid string, token UserToken) error {
+	store[appid+":"+openid] = token
+	return nil
+}
+
+func (store memoryUserTokenStore) Fetch(appid string, openid string) (UserToken, error) {
+	token, ok := store[appid+":"+openid]
+	if !ok {
+		return token, errors.New("not found")
+	}
+	return token, nil
+}
+
+func (store memoryUserTokenStore) Delete(appid string, openid string) error {
+	delete(store, appid+":"+openid)
+	return nil
+}
+
+func TestUserTokenManager_GetUserToken(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiRefreshAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("refresh_token") {
+		case "REFRESH_TOKEN":
+		case "INVALID_REFRESH_TOKEN":
+			_, _ = w.Write([]byte(`{"errcode":40030,"errmsg":"invalid refresh_token"}`))
+			return
+		case "QUOTA_REFRESH_TOKEN":
+			_, _ = w.Write([]byte(`{"errcode":45009,"errmsg":"reach max api daily quota limit"}`))
+			return
+		default:
+			_, _ = w.Write([]byte(`{"errcode":42002,"errmsg":"refresh_token expired"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"NEW_ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_base"}`))
+	})
+
+	now := time.Now()
+	tests := []struct {
+		name            string
+		token           *UserToken
+		wantAccessToken string
+		wantErr         error
+		wantErrcode     int64
+	}{
+		{
+			name:            "valid",
+			token:           &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(time.Hour), RefreshTokenExpireAt: now.Add(time.Hour)},
+			wantAccessToken: "ACCESS_TOKEN",
+		},
+		{
+			name:            "refresh",
+			token:           &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(-time.Hour), RefreshTokenExpireAt: now.Add(time.Hour)},
+			wantAccessToken: "NEW_ACCESS_TOKEN",
+		},
+		{
+			name:    "refresh token expired",
+			token:   &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(-time.Hour), RefreshTokenExpireAt: now.Add(-time.Minute)},
+			wantErr: ErrorReauthorizationRequired,
+		},
+		{
+			name:    "refresh token rejected",
+			token:   &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "DEAD_REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(-time.Hour), RefreshTokenExpireAt: now.Add(time.Hour)},
+			wantErr: ErrorReauthorizationRequired,
+		},
+		{
+			name:    "refresh token invalid",
+			token:   &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "INVALID_REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(-time.Hour), RefreshTokenExpireAt: now.Add(time.Hour)},
+			wantErr: ErrorReauthorizationRequired,
+		},
+		{
+			name:            "quota limit",
+			token:           &UserToken{AccessToken: AccessToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "QUOTA_REFRESH_TOKEN"}, AccessTokenExpireAt: now.Add(-time.Hour), RefreshTokenExpireAt: now.Add(time.Hour)},
+			wantAccessToken: "ACCESS_TOKEN",
+			wantErrcode:     45009,
+		},
+		{
+			name:    "not found",
+			wantErr: ErrorReauthorizationRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memoryUserTokenStore{}
+			if tt.token != nil {
+				_ = store.Save("AUTHORIZER_APPID", "OPENID", *tt.token)
+			}
+			manager := NewUserTokenManager(test.MockPlatform, store)
+
+			got, err := manager.GetUserToken("AUTHORIZER_APPID", "OPENID")
+			if tt.wantErrcode != 0 {
+				var apiError *wxopen.ApiError
+				if !errors.As(err, &apiError) || apiError.Errcode != tt.wantErrcode {
+					t.Errorf("GetUserToken() error = %v, want errcode %d", err, tt.wantErrcode)
+				}
+				if saved, err := store.Fetch("AUTHORIZER_APPID", "OPENID"); err != nil || saved.AccessToken.AccessToken != tt.wantAccessToken {
+					t.Errorf("GetUserToken() token not kept, saved = %+v, err = %v", saved, err)
+				}
+				return
+			}
+			if err != tt.wantErr {
+				t.Errorf("GetUserToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				if _, err := store.Fetch("AUTHORIZER_APPID", "OPENID"); err == nil {
+					t.Errorf("GetUserToken() dead token not deleted")
+				}
+				return
+			}
+			if got.AccessToken.AccessToken != tt.wantAccessToken {
+				t.Errorf("GetUserToken() access_token = %v, want %v", got.AccessToken.AccessToken, tt.wantAccessToken)
+			}
+			if saved, _ := store.Fetch("AUTHORIZER_APPID", "OPENID"); saved.AccessToken.AccessToken != tt.wantAccessToken || !saved.AccessTokenExpireAt.After(now) {
+				t.Errorf("GetUserToken() saved = %+v", saved)
+			}
+		})
+	}
+}
+
+func TestCacheUserTokenStore(t *testing.T) {
+	manager := NewUserTokenManager(test.MockPlatform, nil)
+	saved, err := manager.Save("AUTHORIZER_APPID", AccessToken{AccessToken: "ACCESS_TOKEN", ExpiresIn: 7200, RefreshToken: "REFRESH_TOKEN", Openid: "CACHE_OPENID"})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := manager.GetUserToken("AUTHORIZER_APPID", "CACHE_OPENID")
+	if err != nil || got.AccessToken != saved.AccessToken || !got.RefreshTokenExpireAt.Equal(saved.RefreshTokenExpireAt) {
+		t.Errorf("GetUserToken() = %+v, %v, want %+v", got, err, saved)
+	}
+}
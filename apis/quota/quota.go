@@ -0,0 +1,167 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quota 接口调用频次限制管理
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+
+	"github.com/fastwego/wxopen"
+)
+
+const (
+	apiClearComponentQuota = "/cgi-bin/component/clear_quota"
+	apiClearQuota          = "/cgi-bin/clear_quota"
+	apiClearQuotaV2        = "/cgi-bin/clear_quota/v2"
+	apiGetApiQuota         = "/cgi-bin/openapi/quota/get"
+	apiGetRid              = "/cgi-bin/openapi/rid/get"
+)
+
+// ErrcodeQuotaLimit 接口调用超过限制
+const ErrcodeQuotaLimit = 45009
+
+/*
+第三方平台对其所有 API 调用次数清零
+
+只与第三方平台相关，与公众号 / 小程序无关，每月可清零 10 次
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Official_Accounts/Official_account_interface.html
+
+POST https://api.weixin.qq.com/cgi-bin/component/clear_quota?component_access_token=COMPONENT_ACCESS_TOKEN
+*/
+func ClearComponentQuota(ctx *wxopen.Platform) (err error) {
+	payload, err := json.Marshal(map[string]string{"component_appid": ctx.Config.AppId})
+	if err != nil {
+		return
+	}
+
+	_, err = ctx.Client.HTTPPost(apiClearComponentQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
+	return
+}
+
+/*
+第三方平台代授权方对其所有 API 调用次数清零
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Official_Accounts/Official_account_interface.html
+
+POST https://api.weixin.qq.com/cgi-bin/clear_quota?access_token=ACCESS_TOKEN
+*/
+func ClearAuthorizerQuota(ctx *wxopen.Platform, appid string) (err error) {
+	payload, err := json.Marshal(map[string]string{"appid": appid})
+	if err != nil {
+		return
+	}
+
+	_, err = ctx.Client.HTTPPostWithAuth(wxopen.AuthAuthorizer(appid), apiClearQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
+	return
+}
+
+/*
+使用 AppSecret 重置 API 调用次数
+
+无需 access_token，适用于 access_token 获取次数本身已超限的情况；appid 可以是第三方平台、公众号、小程序
+
+See: https://developers.weixin.qq.com/doc/offiaccount/openApi/clear_quota_v2.html
+
+POST https://api.weixin.qq.com/cgi-bin/clear_quota/v2
+*/
+func ClearQuotaByAppSecret(ctx *wxopen.Platform, appid string, appsecret string) (err error) {
+	params := url.Values{}
+	params.Add("appid", appid)
+	params.Add("appsecret", appsecret)
+
+	// appid 在请求体中，通过 Auth.Appid 计入该 appid 的接口调用
+	_, err = ctx.Client.HTTPPostWithAuth(wxopen.Auth{Mode: wxopen.AuthModeNone, Appid: appid}, apiClearQuotaV2, bytes.NewReader([]byte(params.Encode())), "application/x-www-form-urlencoded")
+	return
+}
+
+// Quota 接口当天调用配额
+type Quota struct {
+	DailyLimit int64 `json:"daily_limit"` // 当天该账号可调用该接口的次数
+	Used       int64 `json:"used"`        // 当天已经调用的次数
+	Remain     int64 `json:"remain"`      // 当天剩余调用次数
+}
+
+/*
+查询授权方 openAPI 调用 quota
+
+cgiPath 为 api 的请求地址，例如 "/cgi-bin/message/custom/send"，不要前缀 "https://api.weixin.qq.com" 及参数
+
+See: https://developers.weixin.qq.com/doc/offiaccount/openApi/get_api_quota.html
+
+POST https://api.weixin.qq.com/cgi-bin/openapi/quota/get?access_token=ACCESS_TOKEN
+*/
+func GetApiQuota(ctx *wxopen.Platform, appid string, cgiPath string) (quota Quota, err error) {
+	payload, err := json.Marshal(map[string]string{"cgi_path": cgiPath})
+	if err != nil {
+		return
+	}
+
+	resp, err := ctx.Client.HTTPPostWithAuth(wxopen.AuthAuthorizer(appid), apiGetApiQuota, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Quota Quota `json:"quota"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	return result.Quota, nil
+}
+
+// RidInfo rid 对应的请求详情
+type RidInfo struct {
+	InvokeTime   int64  `json:"invoke_time"`   // 发起请求的时间戳
+	CostInMs     int64  `json:"cost_in_ms"`    // 请求毫秒级耗时
+	RequestUrl   string `json:"request_url"`   // 请求的 URL 参数
+	RequestBody  string `json:"request_body"`  // post 请求的请求参数
+	ResponseBody string `json:"response_body"` // 接口请求返回参数
+	ClientIp     string `json:"client_ip"`     // 接口请求的客户端 ip
+}
+
+/*
+查询 rid 信息
+
+接口报错时 errmsg 中携带的 rid，可用于查询该次请求的详情，仅支持查询 7 天内的 rid
+
+See: https://developers.weixin.qq.com/doc/offiaccount/openApi/get_rid_info.html
+
+POST https://api.weixin.qq.com/cgi-bin/openapi/rid/get?access_token=ACCESS_TOKEN
+*/
+func GetRid(ctx *wxopen.Platform, appid string, rid string) (ridInfo RidInfo, err error) {
+	payload, err := json.Marshal(map[string]string{"rid": rid})
+	if err != nil {
+		return
+	}
+
+	resp, err := ctx.Client.HTTPPostWithAuth(wxopen.AuthAuthorizer(appid), apiGetRid, bytes.NewReader(payload), "application/json;charset=utf-8")
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Request RidInfo `json:"request"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	return result.Request, nil
+}
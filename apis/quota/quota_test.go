@@ -0,0 +1,108 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/wxopen"
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestClearComponentQuota(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiClearComponentQuota, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Query().Get("component_access_token") != "ACCESS_TOKEN" || string(body) != `{"component_appid":"APPID"}` {
+			_, _ = w.Write([]byte(`{"errcode":48006,"errmsg":"forbid to clear quota because of reaching the limit"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	if err := ClearComponentQuota(test.MockPlatform); err != nil {
+		t.Errorf("ClearComponentQuota() error = %v", err)
+	}
+}
+
+func TestClearQuotaByAppSecret(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiClearQuotaV2, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("appid") != "APPID" || r.PostForm.Get("appsecret") != "SECRET" || r.URL.RawQuery != "" {
+			_, _ = w.Write([]byte(`{"errcode":41004,"errmsg":"appsecret missing"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	counter := wxopen.NewApiCallCounter()
+	test.MockPlatform.RecordApiCallHandler = counter.Record
+	defer func() { test.MockPlatform.RecordApiCallHandler = nil }()
+
+	if err := ClearQuotaByAppSecret(test.MockPlatform, "APPID", "SECRET"); err != nil {
+		t.Errorf("ClearQuotaByAppSecret() error = %v", err)
+	}
+	if got := counter.Count("APPID", apiClearQuotaV2); got != 1 {
+		t.Errorf("ClearQuotaByAppSecret() recorded calls = %d, want 1", got)
+	}
+	if err := ClearQuotaByAppSecret(test.MockPlatform, "APPID", ""); err == nil {
+		t.Errorf("ClearQuotaByAppSecret() error = nil, want error")
+	}
+}
+
+func TestGetApiQuota(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiGetApiQuota, func(w http.ResponseWriter, r *http.Request) {
+		params := map[string]string{}
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &params)
+		if r.URL.Query().Get("access_token") != "AUTHORIZER_ACCESS_TOKEN" || params["cgi_path"] != "/cgi-bin/message/custom/send" {
+			_, _ = w.Write([]byte(`{"errcode":76021,"errmsg":"cgi_path not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","quota":{"daily_limit":10000000,"used":500,"remain":9999500}}`))
+	})
+
+	got, err := GetApiQuota(test.MockPlatform, "AUTHORIZER_APPID", "/cgi-bin/message/custom/send")
+	if err != nil {
+		t.Fatalf("GetApiQuota() error = %v", err)
+	}
+	if want := (Quota{DailyLimit: 10000000, Used: 500, Remain: 9999500}); got != want {
+		t.Errorf("GetApiQuota() got = %v, want %v", got, want)
+	}
+}
+
+func TestGetRid(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiGetRid, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","request":{"invoke_time":1635156704,"cost_in_ms":30,"request_url":"access_token=xxx","request_body":"","response_body":"{\"errcode\":45009}","client_ip":"1.2.3.4"}}`))
+	})
+
+	got, err := GetRid(test.MockPlatform, "AUTHORIZER_APPID", "RID")
+	if err != nil {
+		t.Fatalf("GetRid() error = %v", err)
+	}
+	want := RidInfo{InvokeTime: 1635156704, CostInMs: 30, RequestUrl: "access_token=xxx", ResponseBody: `{"errcode":45009}`, ClientIp: "1.2.3.4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRid() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package release_check 全网发布接入检测
+package release_check
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+	"github.com/fastwego/wxopen"
+	"github.com/fastwego/wxopen/apis/auth"
+)
+
+const (
+	TestOffiAccountAppid = "wx570bc396a51b8ff8" // 全网发布测试公众号
+	TestMiniprogramAppid = "wxd101a85aa106f53e" // 全网发布测试小程序
+
+	testTextContent     = "TESTCOMPONENT_MSG_TYPE_TEXT"
+	queryAuthCodePrefix = "QUERY_AUTH_CODE:"
+	testTextReplySuffix = "_callback"
+	queryAuthCodeSuffix = "_from_api"
+
+	apiSendCustomMessage = "/cgi-bin/message/custom/send"
+)
+
+/*
+Handle 开启全网发布自动化测试响应
+
+为测试公众号/小程序注册消息处理方法，需配合 Server.ServeAuthorizerMessage 使用：
+
+- 收到 TESTCOMPONENT_MSG_TYPE_TEXT 文本消息，被动回复 TESTCOMPONENT_MSG_TYPE_TEXT_callback
+
+- 收到 QUERY_AUTH_CODE:$query_auth_code$ 文本消息，回复空串，并使用 query_auth_code 换取 authorizer_access_token 后通过客服消息发送 $query_auth_code$_from_api
+
+- 其他消息/事件交给 HandleAllAuthorizers 注册的全局处理方法
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Mini_Programs/releases_instructions.html
+*/
+func Handle(ctx *wxopen.Platform) {
+	handler := Handler(ctx)
+	ctx.Server.HandleAuthorizer(TestOffiAccountAppid, handler)
+	ctx.Server.HandleAuthorizer(TestMiniprogramAppid, handler)
+}
+
+/*
+Handler 全网发布自动化测试消息处理方法
+*/
+func Handler(ctx *wxopen.Platform) wxopen.AuthorizerMessageHandlerFunc {
+	return func(appid string, message interface{}) (reply interface{}, err error) {
+		msg, ok := message.(type_message.MessageText)
+
+		switch {
+		case ok && msg.Content == testTextContent:
+			return type_message.ReplyMessageText{
+				ReplyMessage: type_message.ReplyMessage{
+					ToUserName:   type_message.CDATA(msg.FromUserName),
+					FromUserName: type_message.CDATA(msg.ToUserName),
+					CreateTime:   msg.CreateTime,
+					MsgType:      type_message.ReplyMsgTypeText,
+				},
+				Content: type_message.CDATA(testTextContent + testTextReplySuffix),
+			}, nil
+		case ok && strings.HasPrefix(msg.Content, queryAuthCodePrefix):
+			queryAuthCode := strings.TrimPrefix(msg.Content, queryAuthCodePrefix)
+
+			// 须在 5 秒内回复微信服务器，客服消息异步发送
+			go func() {
+				err := sendQueryAuthCodeMessage(ctx, appid, queryAuthCode, msg.FromUserName)
+				if err != nil && ctx.Logger != nil {
+					ctx.Logger.Printf("release check %s %s: %v", appid, queryAuthCode, err)
+				}
+			}()
+			return wxopen.ReplyEmpty, nil
+		}
+
+		// 其他消息交给全局处理方法
+		if handler := ctx.Server.AllAuthorizersHandler(); handler != nil {
+			return handler(appid, message)
+		}
+		return
+	}
+}
+
+// sendQueryAuthCodeMessage 使用 query_auth_code 换取 authorizer_access_token 并发送客服消息
+func sendQueryAuthCodeMessage(ctx *wxopen.Platform, appid string, queryAuthCode string, openid string) (err error) {
+	payload, err := json.Marshal(map[string]string{
+		"component_appid":    ctx.Config.AppId,
+		"authorization_code": queryAuthCode,
+	})
+	if err != nil {
+		return
+	}
+
+	resp, err := auth.ApiQueryAuth(ctx, payload)
+	if err != nil {
+		return
+	}
+
+	queryAuth := struct {
+		AuthorizationInfo struct {
+			AuthorizerAppid       string `json:"authorizer_appid"`
+			AuthorizerAccessToken string `json:"authorizer_access_token"`
+		} `json:"authorization_info"`
+	}{}
+	err = json.Unmarshal(resp, &queryAuth)
+	if err != nil {
+		return
+	}
+
+	payload, err = json.Marshal(map[string]interface{}{
+		"touser":  openid,
+		"msgtype": "text",
+		"text": map[string]string{
+			"content": queryAuthCode + queryAuthCodeSuffix,
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	// 测试账号的 authorizer_access_token 直接使用换取的结果，由调用方放在请求参数中；
+	// 通过 ctx.Client 发送，日志隐藏 access_token，并按 appid 限流、记录接口调用
+	params := url.Values{}
+	params.Add("access_token", queryAuth.AuthorizationInfo.AuthorizerAccessToken)
+	_, err = ctx.Client.HTTPPostWithAuth(wxopen.Auth{Mode: wxopen.AuthModeUser, Appid: appid}, apiSendCustomMessage+"?"+params.Encode(), bytes.NewReader(payload), "application/json;charset=utf-8")
+	return
+}
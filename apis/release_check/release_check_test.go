@@ -0,0 +1,150 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release_check
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+	"github.com/fastwego/wxopen"
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func TestHandle(t *testing.T) {
+	test.MockSvrHandler.HandleFunc("/cgi-bin/component/api_query_auth", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"authorization_info":{"authorizer_appid":"wx570bc396a51b8ff8","authorizer_access_token":"TEST_ACCESS_TOKEN"}}`))
+	})
+	sent := make(chan string, 1)
+	test.MockSvrHandler.HandleFunc("/cgi-bin/message/custom/send", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sent <- r.URL.Query().Get("access_token") + " " + string(body)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	// 客服消息经过平台 client：隐藏 access_token 并记录接口调用
+	logs := &syncBuffer{}
+	counter := wxopen.NewApiCallCounter()
+	logger := test.MockPlatform.Logger
+	test.MockPlatform.Logger = log.New(logs, "", 0)
+	test.MockPlatform.RecordApiCallHandler = counter.Record
+	defer func() {
+		test.MockPlatform.Logger = logger
+		test.MockPlatform.RecordApiCallHandler = nil
+	}()
+
+	Handle(test.MockPlatform)
+	test.MockPlatform.Server.HandleAllAuthorizers(func(appid string, message interface{}) (interface{}, error) {
+		return type_message.ReplyMessageText{
+			ReplyMessage: type_message.ReplyMessage{MsgType: type_message.ReplyMsgTypeText},
+			Content:      type_message.CDATA(fmt.Sprintf("global:%s:%T", appid, message)),
+		}, nil
+	})
+
+	message := func(content string) string {
+		return `<xml><ToUserName><![CDATA[gh_3c884a361561]]></ToUserName><FromUserName><![CDATA[OPENID]]></FromUserName><CreateTime>1488856741</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[` + content + `]]></Content><MsgId>1</MsgId></xml>`
+	}
+	event := `<xml><ToUserName><![CDATA[gh_3c884a361561]]></ToUserName><FromUserName><![CDATA[OPENID]]></FromUserName><CreateTime>1488856741</CreateTime><MsgType><![CDATA[event]]></MsgType><Event><![CDATA[subscribe]]></Event></xml>`
+
+	tests := []struct {
+		name      string
+		appid     string
+		body      string
+		wantReply string
+		wantSent  string
+	}{
+		{name: "text", appid: TestOffiAccountAppid, body: message("TESTCOMPONENT_MSG_TYPE_TEXT"), wantReply: "TESTCOMPONENT_MSG_TYPE_TEXT_callback"},
+		{name: "query auth code", appid: TestMiniprogramAppid, body: message("QUERY_AUTH_CODE:CODE"), wantReply: "", wantSent: `TEST_ACCESS_TOKEN {"msgtype":"text","text":{"content":"CODE_from_api"},"touser":"OPENID"}`},
+		{name: "other text", appid: TestOffiAccountAppid, body: message("hello"), wantReply: "global:" + TestOffiAccountAppid + ":type_message.MessageText"},
+		{name: "event", appid: TestMiniprogramAppid, body: event, wantReply: "global:" + TestMiniprogramAppid + ":type_event.EventSubscribe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := test.NewAuthorizerMessageRequest("/wxopen/"+tt.appid+"/callback", tt.body)
+			recorder := httptest.NewRecorder()
+			err := test.MockPlatform.Server.ServeAuthorizerMessage(recorder, request)
+			if err != nil {
+				t.Errorf("ServeAuthorizerMessage() error = %v", err)
+				return
+			}
+
+			output, err := test.DecryptReply(recorder.Body.Bytes())
+			if err != nil {
+				t.Errorf("DecryptReply() error = %v", err)
+				return
+			}
+			reply := string(output)
+			if strings.HasPrefix(reply, "<xml>") {
+				got := struct{ Content string }{}
+				_ = xml.Unmarshal(output, &got)
+				reply = got.Content
+			}
+			if reply != tt.wantReply {
+				t.Errorf("ServeAuthorizerMessage() reply = %v, want %v", reply, tt.wantReply)
+			}
+
+			if tt.wantSent == "" {
+				return
+			}
+			select {
+			case got := <-sent:
+				if got != tt.wantSent {
+					t.Errorf("custom message = %v, want %v", got, tt.wantSent)
+				}
+				if count := counter.Count(tt.appid, apiSendCustomMessage); count != 1 {
+					t.Errorf("custom message recorded calls = %d, want 1", count)
+				}
+				if strings.Contains(logs.String(), "TEST_ACCESS_TOKEN") {
+					t.Errorf("custom message log contains access_token:\n%s", logs.String())
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("custom message not sent")
+			}
+		})
+	}
+}
+
+// syncBuffer 并发安全的日志缓冲
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wxlogin 网站应用 / 移动应用微信登录
+package wxlogin
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/fastwego/wxopen"
+)
+
+const (
+	apiQRConnect          = "https://open.weixin.qq.com/connect/qrconnect"
+	apiGetAccessToken     = "/sns/oauth2/access_token"
+	apiRefreshAccessToken = "/sns/oauth2/refresh_token"
+	apiCheckAccessToken   = "/sns/auth"
+	apiGetUserInfo        = "/sns/userinfo"
+)
+
+// ScopeLogin 网站应用微信登录授权作用域
+const ScopeLogin = "snsapi_login"
+
+// ScopeUserInfo 移动应用微信登录授权作用域
+const ScopeUserInfo = "snsapi_userinfo"
+
+/*
+Config 网站应用 / 移动应用配置
+
+与第三方平台 PlatformConfig 相互独立，在开放平台管理中心 - 网站应用 / 移动应用中获取
+*/
+type Config struct {
+	AppId     string
+	AppSecret string
+}
+
+/*
+WXLogin 微信登录实例
+
+接口均使用应用 appid/secret 或用户 access_token 鉴权，不依赖 component_access_token
+*/
+type WXLogin struct {
+	Ctx    *wxopen.Platform
+	Config Config
+}
+
+/*
+创建微信登录实例
+
+ctx 仅用于发送请求，可以是未配置第三方平台信息的 wxopen.NewPlatform(wxopen.PlatformConfig{})
+*/
+func New(ctx *wxopen.Platform, config Config) *WXLogin {
+	return &WXLogin{Ctx: ctx, Config: config}
+}
+
+/*
+获取网站应用微信扫码登录链接
+
+See: https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Wechat_Login.html
+
+GET https://open.weixin.qq.com/connect/qrconnect?appid=APPID&redirect_uri=REDIRECT_URI&response_type=code&scope=SCOPE&state=STATE#wechat_redirect
+*/
+func (login *WXLogin) GetQRConnectUrl(redirectUri string, state string) (uri string) {
+	params := url.Values{}
+	params.Add("appid", login.Config.AppId)
+	params.Add("redirect_uri", redirectUri)
+	params.Add("response_type", "code")
+	params.Add("scope", ScopeLogin)
+	params.Add("state", state)
+
+	return apiQRConnect + "?" + params.Encode() + "#wechat_redirect"
+}
+
+type AccessToken struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Openid       string `json:"openid"`
+	Scope        string `json:"scope"`
+	Unionid      string `json:"unionid"`
+}
+
+/*
+通过 code 获取 access_token
+
+See: https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Wechat_Login.html
+
+GET https://api.weixin.qq.com/sns/oauth2/access_token?appid=APPID&secret=SECRET&code=CODE&grant_type=authorization_code
+*/
+func (login *WXLogin) GetAccessToken(code string) (accessToken AccessToken, err error) {
+	params := url.Values{}
+	params.Add("appid", login.Config.AppId)
+	params.Add("secret", login.Config.AppSecret)
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+
+	resp, err := login.Ctx.Client.HTTPGetWithAuth(wxopen.AuthNone, apiGetAccessToken+"?"+params.Encode())
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &accessToken)
+	return
+}
+
+/*
+刷新 access_token 有效期
+
+refresh_token 拥有较长的有效期（30 天），当 refresh_token 失效的后，需要用户重新授权
+
+See: https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Authorization_interface_calling.html
+
+GET https://api.weixin.qq.com/sns/oauth2/refresh_token?appid=APPID&grant_type=refresh_token&refresh_token=REFRESH_TOKEN
+*/
+func (login *WXLogin) RefreshAccessToken(refreshToken string) (accessToken AccessToken, err error) {
+	params := url.Values{}
+	params.Add("appid", login.Config.AppId)
+	params.Add("grant_type", "refresh_token")
+	params.Add("refresh_token", refreshToken)
+
+	resp, err := login.Ctx.Client.HTTPGetWithAuth(wxopen.AuthNone, apiRefreshAccessToken+"?"+params.Encode())
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &accessToken)
+	return
+}
+
+/*
+检验授权凭证 access_token 是否有效
+
+有效时返回 nil；接口返回错误码时返回 *wxopen.ApiError，可用 errors.As 取出 Errcode 判断：
+
+- 42001 access_token 已过期，可用 RefreshAccessToken 刷新
+
+- 40001 access_token 无效，40003 openid 无效，需要用户重新授权
+
+See: https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Authorization_interface_calling.html
+
+GET https://api.weixin.qq.com/sns/auth?access_token=ACCESS_TOKEN&openid=OPENID
+*/
+func (login *WXLogin) CheckAccessToken(accessToken string, openid string) (err error) {
+	params := url.Values{}
+	params.Add("access_token", accessToken)
+	params.Add("openid", openid)
+
+	_, err = login.Ctx.Client.HTTPGetWithAuth(wxopen.AuthUser, apiCheckAccessToken+"?"+params.Encode())
+	return
+}
+
+type UserInfo struct {
+	Openid     string   `json:"openid"`
+	Nickname   string   `json:"nickname"`
+	Sex        int64    `json:"sex"`
+	Province   string   `json:"province"`
+	City       string   `json:"city"`
+	Country    string   `json:"country"`
+	Headimgurl string   `json:"headimgurl"`
+	Privilege  []string `json:"privilege"`
+	Unionid    string   `json:"unionid"`
+}
+
+/*
+获取用户个人信息（UnionID 机制）
+
+同一开放平台帐号下的网站应用、移动应用、公众号、小程序，同一用户的 unionid 是唯一的
+
+See: https://developers.weixin.qq.com/doc/oplatform/Website_App/WeChat_Login/Authorization_interface_calling.html
+
+GET https://api.weixin.qq.com/sns/userinfo?access_token=ACCESS_TOKEN&openid=OPENID
+*/
+func (login *WXLogin) GetUserInfo(accessToken string, openid string) (userInfo UserInfo, err error) {
+	params := url.Values{}
+	params.Add("access_token", accessToken)
+	params.Add("openid", openid)
+	params.Add("lang", "zh_CN")
+
+	resp, err := login.Ctx.Client.HTTPGetWithAuth(wxopen.AuthUser, apiGetUserInfo+"?"+params.Encode())
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &userInfo)
+	return
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxlogin
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fastwego/wxopen"
+	"github.com/fastwego/wxopen/test"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+	os.Exit(m.Run())
+}
+
+func newTestLogin() *WXLogin {
+	return New(test.MockPlatform, Config{AppId: "WEBAPP_APPID", AppSecret: "WEBAPP_SECRET"})
+}
+
+func TestWXLogin_GetQRConnectUrl(t *testing.T) {
+	got := newTestLogin().GetQRConnectUrl("https://example.com/callback?a=1", "STATE")
+	want := "https://open.weixin.qq.com/connect/qrconnect?appid=WEBAPP_APPID&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Fa%3D1&response_type=code&scope=snsapi_login&state=STATE#wechat_redirect"
+	if got != want {
+		t.Errorf("GetQRConnectUrl() = %v, want %v", got, want)
+	}
+}
+
+func TestWXLogin_GetAccessToken(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiGetAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "WEBAPP_APPID" || q.Get("secret") != "WEBAPP_SECRET" || q.Get("code") != "CODE" ||
+			q.Get("component_access_token") != "" || q.Get("access_token") != "" {
+			_, _ = w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"USER_ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_login","unionid":"UNIONID"}`))
+	})
+
+	got, err := newTestLogin().GetAccessToken("CODE")
+	if err != nil {
+		t.Fatalf("GetAccessToken() error = %v", err)
+	}
+	want := AccessToken{AccessToken: "USER_ACCESS_TOKEN", ExpiresIn: 7200, RefreshToken: "REFRESH_TOKEN", Openid: "OPENID", Scope: "snsapi_login", Unionid: "UNIONID"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAccessToken() got = %v, want %v", got, want)
+	}
+
+	_, err = newTestLogin().GetAccessToken("INVALID")
+	var apiError *wxopen.ApiError
+	if !errors.As(err, &apiError) || apiError.Errcode != 40029 {
+		t.Errorf("GetAccessToken() error = %v, want errcode 40029", err)
+	}
+}
+
+func TestWXLogin_RefreshAccessToken(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiRefreshAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "WEBAPP_APPID" || q.Get("grant_type") != "refresh_token" || q.Get("refresh_token") != "REFRESH_TOKEN" ||
+			q.Get("component_access_token") != "" {
+			_, _ = w.Write([]byte(`{"errcode":40030,"errmsg":"invalid refresh_token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"NEW_ACCESS_TOKEN","expires_in":7200,"refresh_token":"REFRESH_TOKEN","openid":"OPENID","scope":"snsapi_login"}`))
+	})
+
+	got, err := newTestLogin().RefreshAccessToken("REFRESH_TOKEN")
+	if err != nil {
+		t.Fatalf("RefreshAccessToken() error = %v", err)
+	}
+	want := AccessToken{AccessToken: "NEW_ACCESS_TOKEN", ExpiresIn: 7200, RefreshToken: "REFRESH_TOKEN", Openid: "OPENID", Scope: "snsapi_login"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RefreshAccessToken() got = %v, want %v", got, want)
+	}
+
+	_, err = newTestLogin().RefreshAccessToken("EXPIRED_REFRESH_TOKEN")
+	var apiError *wxopen.ApiError
+	if !errors.As(err, &apiError) || apiError.Errcode != 40030 {
+		t.Errorf("RefreshAccessToken() error = %v, want errcode 40030", err)
+	}
+}
+
+func TestWXLogin_CheckAccessToken(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiCheckAccessToken, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("access_token") {
+		case "USER_ACCESS_TOKEN":
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case "EXPIRED":
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+		default:
+			_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+		}
+	})
+
+	if err := newTestLogin().CheckAccessToken("USER_ACCESS_TOKEN", "OPENID"); err != nil {
+		t.Errorf("CheckAccessToken() error = %v", err)
+	}
+
+	tests := []struct {
+		accessToken string
+		errcode     int64
+	}{
+		{accessToken: "EXPIRED", errcode: 42001},
+		{accessToken: "INVALID", errcode: 40001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accessToken, func(t *testing.T) {
+			err := newTestLogin().CheckAccessToken(tt.accessToken, "OPENID")
+			var apiError *wxopen.ApiError
+			if !errors.As(err, &apiError) || apiError.Errcode != tt.errcode {
+				t.Errorf("CheckAccessToken() error = %v, want errcode %d", err, tt.errcode)
+			}
+		})
+	}
+}
+
+func TestWXLogin_GetUserInfo(t *testing.T) {
+	test.MockSvrHandler.HandleFunc(apiGetUserInfo, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"openid":"OPENID","nickname":"NICKNAME","sex":1,"unionid":"UNIONID"}`))
+	})
+
+	got, err := newTestLogin().GetUserInfo("USER_ACCESS_TOKEN", "OPENID")
+	if err != nil {
+		t.Fatalf("GetUserInfo() error = %v", err)
+	}
+	if got.Unionid != "UNIONID" || got.Nickname != "NICKNAME" {
+		t.Errorf("GetUserInfo() got = %v", got)
+	}
+}
@@ -15,12 +15,15 @@
 package wxopen
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -29,8 +32,59 @@ var (
 	UserAgent                       = "fastwego/wxopen"
 	ErrorComponentAccessTokenExpire = errors.New("component_access_token expire")
 	ErrorSystemBusy                 = errors.New("system busy")
+	ErrorBodyNotReplayable          = errors.New("request body can not be replayed for retry") // 流式请求体无法重试
 )
 
+/*
+ApiError 微信 api 接口响应的错误码 errcode 不为 0
+
+Error() 返回接口原始响应
+*/
+type ApiError struct {
+	Errcode  int64  `json:"errcode"`
+	Errmsg   string `json:"errmsg"`
+	Response []byte `json:"-"`
+}
+
+func (e *ApiError) Error() string {
+	return string(e.Response)
+}
+
+// AuthMode 接口鉴权方式
+type AuthMode int
+
+const (
+	AuthModeComponent  AuthMode = iota // 附加第三方平台 component_access_token
+	AuthModeAuthorizer                 // 附加授权方 authorizer_access_token
+	AuthModeUser                       // 使用用户网页授权 access_token，由调用方放在请求参数中
+	AuthModeNone                       // 不需要令牌
+)
+
+/*
+Auth 请求鉴权信息
+*/
+type Auth struct {
+	Mode       AuthMode
+	Appid      string // AuthModeAuthorizer 时为授权方 appid；其他模式可选，为记录接口调用的 appid
+	TokenParam string // 非默认的令牌参数名，例如以 access_token 传递 component_access_token
+}
+
+var (
+	AuthComponent = Auth{Mode: AuthModeComponent}
+	AuthUser      = Auth{Mode: AuthModeUser}
+	AuthNone      = Auth{Mode: AuthModeNone}
+)
+
+// AuthComponentTokenParam 使用 component_access_token 鉴权，令牌放在 param 参数中（过期重试时同样刷新该参数）
+func AuthComponentTokenParam(param string) Auth {
+	return Auth{Mode: AuthModeComponent, TokenParam: param}
+}
+
+// AuthAuthorizer 使用授权方 authorizer_access_token 鉴权
+func AuthAuthorizer(appid string) Auth {
+	return Auth{Mode: AuthModeAuthorizer, Appid: appid}
+}
+
 /*
 Client 用于向微信接口发送请求
 */
@@ -38,9 +92,19 @@ type Client struct {
 	Ctx *Platform
 }
 
-// HTTPGet GET 请求
+// HTTPGet GET 请求 (使用 component_access_token)
 func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
-	newUrl, err := client.applyComponentAccessToken(uri)
+	return client.HTTPGetWithAuth(AuthComponent, uri)
+}
+
+//HTTPPost POST 请求 (使用 component_access_token)
+func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string) (resp []byte, err error) {
+	return client.HTTPPostWithAuth(AuthComponent, uri, payload, contentType)
+}
+
+// HTTPGetWithAuth 以指定鉴权方式发起 GET 请求
+func (client *Client) HTTPGetWithAuth(auth Auth, uri string) (resp []byte, err error) {
+	newUrl, err := client.applyAccessToken(auth, uri)
 	if err != nil {
 		return
 	}
@@ -50,12 +114,52 @@ func (client *Client) HTTPGet(uri string) (resp []byte, err error) {
 		return
 	}
 
-	return client.httpDo(req)
+	return client.httpDo(auth, req)
 }
 
-//HTTPPost POST 请求
-func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string) (resp []byte, err error) {
-	newUrl, err := client.applyComponentAccessToken(uri)
+// HTTPPostWithAuth 以指定鉴权方式发起 POST 请求
+func (client *Client) HTTPPostWithAuth(auth Auth, uri string, payload io.Reader, contentType string) (resp []byte, err error) {
+	newUrl, err := client.applyAccessToken(auth, uri)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, WXServerUrl+newUrl, payload)
+	if err != nil {
+		return
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return client.httpDo(auth, req)
+}
+
+/*
+HTTPGetRawWithAuth 以指定鉴权方式发起 GET 请求，用于返回图片等二进制内容的接口
+
+返回原始响应体及其 Content-Type；接口以 json 返回错误时与 HTTPGetWithAuth 一致
+*/
+func (client *Client) HTTPGetRawWithAuth(auth Auth, uri string) (resp []byte, contentType string, err error) {
+	newUrl, err := client.applyAccessToken(auth, uri)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodGet, WXServerUrl+newUrl, nil)
+	if err != nil {
+		return
+	}
+
+	return client.httpDoRaw(auth, req)
+}
+
+/*
+HTTPPostRawWithAuth 以指定鉴权方式发起 POST 请求，用于返回图片等二进制内容的接口
+
+返回原始响应体及其 Content-Type；接口以 json 返回错误时与 HTTPPostWithAuth 一致
+*/
+func (client *Client) HTTPPostRawWithAuth(auth Auth, uri string, payload io.Reader, contentType string) (resp []byte, respContentType string, err error) {
+	newUrl, err := client.applyAccessToken(auth, uri)
 	if err != nil {
 		return
 	}
@@ -67,15 +171,24 @@ func (client *Client) HTTPPost(uri string, payload io.Reader, contentType string
 
 	req.Header.Add("Content-Type", contentType)
 
-	return client.httpDo(req)
+	return client.httpDoRaw(auth, req)
 }
 
 //httpDo 执行 请求
-func (client *Client) httpDo(req *http.Request) (resp []byte, err error) {
-	req.Header.Add("User-Agent", UserAgent)
+func (client *Client) httpDo(auth Auth, req *http.Request) (resp []byte, err error) {
+	resp, _, err = client.httpDoRaw(auth, req)
+	return
+}
 
-	if client.Ctx.Logger != nil {
-		client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, req.URL.String(), req.Header)
+/*
+httpDoRaw 执行请求，返回响应体及其 Content-Type
+
+图片等二进制响应原样返回；json 响应检查错误码，access_token 过期 / 系统繁忙时重试
+*/
+func (client *Client) httpDoRaw(auth Auth, req *http.Request) (resp []byte, contentType string, err error) {
+	err = client.beforeDo(auth, req)
+	if err != nil {
+		return
 	}
 
 	response, err := http.DefaultClient.Do(req)
@@ -85,83 +198,200 @@ func (client *Client) httpDo(req *http.Request) (resp []byte, err error) {
 	defer response.Body.Close()
 
 	resp, err = responseFilter(response)
+	contentType = response.Header.Get("Content-Type")
 
-	// 发现 component_access_token 过期
+	// 发现 access_token 过期
 	if err == ErrorComponentAccessTokenExpire {
-
-		// 主动 通知 component_access_token 过期
-		err = client.Ctx.NoticeComponentAccessTokenExpireHandler(client.Ctx)
-		if err != nil {
-			return
-		}
-
-		// 通知到位后 component_access_token 会被刷新，那么可以 retry 了
-		var accessToken string
-		accessToken, err = client.Ctx.GetComponentAccessTokenHandler(client.Ctx)
-		if err != nil {
+		switch auth.Mode {
+		case AuthModeComponent, AuthModeAuthorizer:
+			// 主动通知 access_token 过期
+			err = client.noticeAccessTokenExpire(auth)
+			if err != nil {
+				return
+			}
+
+			// 通知到位后 access_token 会被刷新，那么可以 retry 了
+			var accessToken string
+			accessToken, err = client.getAccessToken(auth)
+			if err != nil {
+				return
+			}
+
+			// 换新
+			q := req.URL.Query()
+			q.Set(accessTokenParam(auth), accessToken)
+			req.URL.RawQuery = q.Encode()
+
+			if client.Ctx.Logger != nil {
+				client.Ctx.Logger.Printf("%v retry %s %s Headers %v", ErrorComponentAccessTokenExpire, req.Method, redactUrl(req.URL), req.Header)
+			}
+
+			response, err = client.retry(auth, req)
+			if err != nil {
+				return
+			}
+			defer response.Body.Close()
+
+			resp, err = responseFilter(response)
+			contentType = response.Header.Get("Content-Type")
+		default:
+			// 用户令牌过期由调用方处理，不应影响 component_access_token
+			err = newApiError(resp)
 			return
 		}
+	}
 
-		// 换新
-		q := req.URL.Query()
-		q.Set("component_access_token", accessToken)
-		req.URL.RawQuery = q.Encode()
+	// -1 系统繁忙，此时请开发者稍候再试
+	// 重试一次
+	if err == ErrorSystemBusy {
 
 		if client.Ctx.Logger != nil {
-			client.Ctx.Logger.Printf("%v retry %s %s Headers %v", ErrorComponentAccessTokenExpire, req.Method, req.URL.String(), req.Header)
+			client.Ctx.Logger.Printf("%v : retry %s %s Headers %v", ErrorSystemBusy, req.Method, redactUrl(req.URL), req.Header)
 		}
 
-		response, err = http.DefaultClient.Do(req)
+		response, err = client.retry(auth, req)
 		if err != nil {
 			return
 		}
 		defer response.Body.Close()
 
 		resp, err = responseFilter(response)
+		contentType = response.Header.Get("Content-Type")
 	}
 
-	// -1 系统繁忙，此时请开发者稍候再试
-	// 重试一次
-	if err == ErrorSystemBusy {
+	return
+}
 
-		if client.Ctx.Logger != nil {
-			client.Ctx.Logger.Printf("%v : retry %s %s Headers %v", ErrorSystemBusy, req.Method, req.URL.String(), req.Header)
-		}
+// beforeDo 发送请求前设置请求头、限流与记录接口调用
+func (client *Client) beforeDo(auth Auth, req *http.Request) (err error) {
+	req.Header.Add("User-Agent", UserAgent)
+
+	if client.Ctx.Logger != nil {
+		client.Ctx.Logger.Printf("%s %s Headers %v", req.Method, redactUrl(req.URL), req.Header)
+	}
+
+	return client.limitAndRecord(auth, req)
+}
+
+// limitAndRecord 请求发出前限流并记录接口调用
+func (client *Client) limitAndRecord(auth Auth, req *http.Request) (err error) {
+	return client.Ctx.limitAndRecord(client.callerAppid(auth, req), req.URL.Path)
+}
+
+/*
+retry 重新发送请求 (重置请求体)，重试同样计入限流与接口调用记录
+
+io.Pipe 等流式请求体无法重置，不重试，返回 ErrorBodyNotReplayable
+*/
+func (client *Client) retry(auth Auth, req *http.Request) (response *http.Response, err error) {
+	if req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+		return nil, ErrorBodyNotReplayable
+	}
+
+	err = client.limitAndRecord(auth, req)
+	if err != nil {
+		return
+	}
 
-		response, err = http.DefaultClient.Do(req)
+	if req.GetBody != nil {
+		req.Body, err = req.GetBody()
 		if err != nil {
 			return
 		}
-		defer response.Body.Close()
-
-		resp, err = responseFilter(response)
 	}
-
-	return
+	return http.DefaultClient.Do(req)
 }
 
 /*
-在请求地址上附加上 component_access_token
+在请求地址上附加上鉴权方式对应的 access_token
 */
-func (client *Client) applyComponentAccessToken(oldUrl string) (newUrl string, err error) {
-	accessToken, err := client.Ctx.GetComponentAccessTokenHandler(client.Ctx)
+func (client *Client) applyAccessToken(auth Auth, oldUrl string) (newUrl string, err error) {
+	if auth.Mode != AuthModeComponent && auth.Mode != AuthModeAuthorizer {
+		return oldUrl, nil
+	}
+
+	accessToken, err := client.getAccessToken(auth)
 	if err != nil {
 		return
 	}
 	if strings.Contains(oldUrl, "?") {
-		newUrl = oldUrl + "&component_access_token=" + accessToken
+		newUrl = oldUrl + "&" + accessTokenParam(auth) + "=" + accessToken
 	} else {
-		newUrl = oldUrl + "?component_access_token=" + accessToken
+		newUrl = oldUrl + "?" + accessTokenParam(auth) + "=" + accessToken
 	}
 	return
 }
 
+// getAccessToken 获取鉴权方式对应的 access_token
+func (client *Client) getAccessToken(auth Auth) (accessToken string, err error) {
+	if auth.Mode == AuthModeAuthorizer {
+		return client.Ctx.GetAuthorizerAccessTokenHandler(client.Ctx, auth.Appid)
+	}
+	return client.Ctx.GetComponentAccessTokenHandler(client.Ctx)
+}
+
+// noticeAccessTokenExpire 通知鉴权方式对应的 access_token 过期
+func (client *Client) noticeAccessTokenExpire(auth Auth) (err error) {
+	if auth.Mode == AuthModeAuthorizer {
+		return client.Ctx.NoticeAuthorizerAccessTokenExpireHandler(client.Ctx, auth.Appid)
+	}
+	return client.Ctx.NoticeComponentAccessTokenExpireHandler(client.Ctx)
+}
+
+// callerAppid 接口调用所属 appid
+func (client *Client) callerAppid(auth Auth, req *http.Request) string {
+	switch auth.Mode {
+	case AuthModeComponent:
+		return client.Ctx.Config.AppId
+	case AuthModeAuthorizer:
+		return auth.Appid
+	default:
+		// 用户令牌 / 无鉴权接口一般在参数中携带 appid；appid 在请求体中时由 auth.Appid 指定
+		if auth.Appid != "" {
+			return auth.Appid
+		}
+		return req.URL.Query().Get("appid")
+	}
+}
+
+// redactUrl 日志中隐藏 secret 及各类 token 参数值
+func redactUrl(u *url.URL) string {
+	q := u.Query()
+	redacted := false
+	for k := range q {
+		if k == "secret" || strings.HasSuffix(k, "_token") {
+			q.Set(k, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// accessTokenParam 鉴权方式对应的 access_token 请求参数名
+func accessTokenParam(auth Auth) string {
+	if auth.TokenParam != "" {
+		return auth.TokenParam
+	}
+	if auth.Mode == AuthModeAuthorizer {
+		return "access_token"
+	}
+	return "component_access_token"
+}
+
 /*
 筛查微信 api 服务器响应，判断以下错误：
 
 - http 状态码 不为 200
 
 - 接口响应错误码 errcode 不为 0
+
+图片等二进制响应不解析，直接返回
 */
 func responseFilter(response *http.Response) (resp []byte, err error) {
 	if response.StatusCode != http.StatusOK {
@@ -174,6 +404,10 @@ func responseFilter(response *http.Response) (resp []byte, err error) {
 		return
 	}
 
+	if !isJSONResponse(response.Header.Get("Content-Type"), resp) {
+		return
+	}
+
 	errorResponse := struct {
 		Errcode int64  `json:"errcode"`
 		Errmsg  string `json:"errmsg"`
@@ -197,8 +431,36 @@ func responseFilter(response *http.Response) (resp []byte, err error) {
 	}
 
 	if errorResponse.Errcode != 0 {
-		err = errors.New(string(resp))
+		err = newApiError(resp)
 		return
 	}
 	return
 }
+
+/*
+isJSONResponse 判断响应是否为 json
+
+微信返回错误时 Content-Type 可能为 application/json 或 text/plain，缺省或 text 类型时按内容判断
+*/
+func isJSONResponse(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+
+	switch {
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return true
+	case mediaType == "", strings.HasPrefix(mediaType, "text/"):
+		body = bytes.TrimSpace(body)
+		return len(body) > 0 && body[0] == '{'
+	}
+	return false
+}
+
+// newApiError 由接口响应生成 ApiError
+func newApiError(resp []byte) (apiError *ApiError) {
+	apiError = &ApiError{Response: resp}
+	_ = json.Unmarshal(resp, apiError)
+	return
+}
@@ -0,0 +1,345 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestClient_HTTPPostWithAuth(t *testing.T) {
+	var notices []string
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	componentAccessToken := "COMPONENT_ACCESS_TOKEN"
+	platform.GetComponentAccessTokenHandler = func(platform *Platform) (string, error) {
+		return componentAccessToken, nil
+	}
+	platform.NoticeComponentAccessTokenExpireHandler = func(platform *Platform) error {
+		notices = append(notices, "component")
+		componentAccessToken = "COMPONENT_ACCESS_TOKEN"
+		return nil
+	}
+	authorizerAccessToken := "EXPIRED_ACCESS_TOKEN"
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return authorizerAccessToken, nil
+	}
+	platform.NoticeAuthorizerAccessTokenExpireHandler = func(platform *Platform, appid string) error {
+		notices = append(notices, "authorizer:"+appid)
+		authorizerAccessToken = "AUTHORIZER_ACCESS_TOKEN"
+		return nil
+	}
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		q := r.URL.Query()
+		switch {
+		case q.Get("access_token") == "EXPIRED_ACCESS_TOKEN", q.Get("access_token") == "USER_EXPIRED", q.Get("access_token") == "EXPIRED_COMPONENT_ACCESS_TOKEN":
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+		default:
+			resp, _ := json.Marshal(map[string]string{"query": r.URL.RawQuery, "body": string(body)})
+			_, _ = w.Write(resp)
+		}
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	tests := []struct {
+		name        string
+		auth        Auth
+		uri         string
+		want        string
+		expired     bool // component_access_token 已过期
+		wantErrcode int64
+		wantNotices []string
+	}{
+		{name: "component", auth: AuthComponent, uri: "/api", want: `{"body":"{}","query":"component_access_token=COMPONENT_ACCESS_TOKEN"}`},
+		{name: "component token param", auth: AuthComponentTokenParam("access_token"), uri: "/api", expired: true, want: `{"body":"{}","query":"access_token=COMPONENT_ACCESS_TOKEN"}`, wantNotices: []string{"component"}},
+		{name: "authorizer", auth: AuthAuthorizer("AUTHORIZER_APPID"), uri: "/api", want: `{"body":"{}","query":"access_token=AUTHORIZER_ACCESS_TOKEN"}`, wantNotices: []string{"authorizer:AUTHORIZER_APPID"}},
+		{name: "user", auth: AuthUser, uri: "/api?access_token=USER_ACCESS_TOKEN", want: `{"body":"{}","query":"access_token=USER_ACCESS_TOKEN"}`},
+		{name: "user expired", auth: AuthUser, uri: "/api?access_token=USER_EXPIRED", wantErrcode: 42001},
+		{name: "none", auth: AuthNone, uri: "/api", want: `{"body":"{}","query":""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notices = nil
+			if tt.expired {
+				componentAccessToken = "EXPIRED_COMPONENT_ACCESS_TOKEN"
+			}
+			resp, err := platform.Client.HTTPPostWithAuth(tt.auth, tt.uri, strings.NewReader("{}"), "application/json;charset=utf-8")
+			if tt.wantErrcode != 0 {
+				var apiError *ApiError
+				if !errors.As(err, &apiError) || apiError.Errcode != tt.wantErrcode {
+					t.Errorf("HTTPPostWithAuth() error = %v, wantErrcode %v", err, tt.wantErrcode)
+				}
+			} else if err != nil || string(resp) != tt.want {
+				t.Errorf("HTTPPostWithAuth() = %s, %v, want %s", resp, err, tt.want)
+			}
+			if strings.Join(notices, ",") != strings.Join(tt.wantNotices, ",") {
+				t.Errorf("HTTPPostWithAuth() notices = %v, want %v", notices, tt.wantNotices)
+			}
+		})
+	}
+}
+
+func TestClient_RecordApiCall(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	platform.GetComponentAccessTokenHandler = func(platform *Platform) (string, error) {
+		return "COMPONENT_ACCESS_TOKEN", nil
+	}
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return appid + "_ACCESS_TOKEN", nil
+	}
+	counter := NewApiCallCounter()
+	platform.RecordApiCallHandler = counter.Record
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system busy"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+	offiaccountServerUrl := offiaccount.WXServerUrl
+	offiaccount.WXServerUrl = svr.URL
+	defer func() { offiaccount.WXServerUrl = offiaccountServerUrl }()
+
+	_, _ = platform.Client.HTTPGet("/component")
+	_, _ = platform.Client.HTTPGet("/component")
+	_, _ = platform.Client.HTTPGet("/busy")
+	_, _ = platform.Client.HTTPGetWithAuth(AuthAuthorizer("AUTHORIZER_APPID"), "/authorizer?a=1")
+	_, _ = platform.Client.HTTPGetWithAuth(AuthNone, "/none?appid=WEBAPP_APPID")
+	_, _ = platform.Client.HTTPPostWithAuth(Auth{Mode: AuthModeNone, Appid: "BODY_APPID"}, "/body", strings.NewReader("appid=BODY_APPID"), "application/x-www-form-urlencoded")
+
+	// 授权方公众号实例
+	offiAccount, _ := platform.NewOffiAccount("AUTHORIZER_APPID")
+	_, _ = offiAccount.Client.HTTPGet("/offiaccount")
+	_, _ = offiAccount.Client.HTTPGet("/busy")
+
+	// 记录不依赖实例的 Logger
+	silent, _ := platform.NewOffiAccount("SILENT_APPID")
+	silent.Logger = nil
+	_, _ = silent.Client.HTTPGet("/offiaccount")
+
+	want := map[string]map[string]int64{
+		"APPID":            {"/component": 2, "/busy": 2},
+		"AUTHORIZER_APPID": {"/authorizer": 1, "/offiaccount": 1, "/busy": 2},
+		"SILENT_APPID":     {"/offiaccount": 1},
+		"WEBAPP_APPID":     {"/none": 1},
+		"BODY_APPID":       {"/body": 1},
+	}
+	if got := counter.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ApiCallCounter.Counts() = %v, want %v", got, want)
+	}
+
+	counter.Reset()
+	if got := counter.Count("APPID", "/component"); got != 0 {
+		t.Errorf("ApiCallCounter.Count() after Reset = %v, want 0", got)
+	}
+}
+
+func TestClient_LogRedactsSecret(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	buf := &bytes.Buffer{}
+	platform.Logger = log.New(buf, "", 0)
+	platform.GetComponentAccessTokenHandler = func(platform *Platform) (string, error) {
+		return "COMPONENT_ACCESS_TOKEN", nil
+	}
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system busy"}`))
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	_, _ = platform.Client.HTTPGet("/component?refresh_token=REFRESH_TOKEN")
+	_, _ = platform.Client.HTTPGetWithAuth(AuthNone, "/sns/oauth2/access_token?appid=WEBAPP_APPID&secret=WEBAPP_SECRET&code=CODE")
+
+	logs := buf.String()
+	for _, secret := range []string{"COMPONENT_ACCESS_TOKEN", "REFRESH_TOKEN", "WEBAPP_SECRET"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("log contains %s:\n%s", secret, logs)
+		}
+	}
+	if !strings.Contains(logs, "appid=WEBAPP_APPID") || !strings.Contains(logs, "secret=REDACTED") || strings.Count(logs, "retry") != 2 {
+		t.Errorf("log = %s", logs)
+	}
+}
+
+func TestClient_RetryStreamBody(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	platform.GetComponentAccessTokenHandler = func(platform *Platform) (string, error) {
+		return "COMPONENT_ACCESS_TOKEN", nil
+	}
+
+	requests := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system busy"}`))
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	r, w := io.Pipe()
+	go func() {
+		_, _ = w.Write([]byte("media"))
+		_ = w.Close()
+	}()
+	if _, err := platform.Client.HTTPPost("/upload", r, "application/octet-stream"); err != ErrorBodyNotReplayable {
+		t.Errorf("HTTPPost() error = %v, want %v", err, ErrorBodyNotReplayable)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestClient_HTTPGetRawWithAuth(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	authorizerAccessToken := "AUTHORIZER_ACCESS_TOKEN"
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return authorizerAccessToken, nil
+	}
+	platform.NoticeAuthorizerAccessTokenExpireHandler = func(platform *Platform, appid string) error {
+		authorizerAccessToken = "AUTHORIZER_ACCESS_TOKEN"
+		return nil
+	}
+	image := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("access_token") {
+		case "AUTHORIZER_ACCESS_TOKEN":
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write(image)
+		case "EXPIRED_ACCESS_TOKEN":
+			w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+			_, _ = w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+		case "":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+			_, _ = w.Write([]byte(`{"errcode":61003,"errmsg":"component is not authorized by this account"}`))
+		}
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	tests := []struct {
+		name            string
+		auth            Auth
+		accessToken     string
+		wantResp        []byte
+		wantContentType string
+		wantErrcode     int64
+		wantErr         bool
+	}{
+		{name: "image", auth: AuthAuthorizer("AUTHORIZER_APPID"), accessToken: "AUTHORIZER_ACCESS_TOKEN", wantResp: image, wantContentType: "image/jpeg"},
+		{name: "token expire", auth: AuthAuthorizer("AUTHORIZER_APPID"), accessToken: "EXPIRED_ACCESS_TOKEN", wantResp: image, wantContentType: "image/jpeg"},
+		{name: "json error", auth: AuthAuthorizer("AUTHORIZER_APPID"), accessToken: "UNAUTHORIZED_ACCESS_TOKEN", wantErrcode: 61003},
+		{name: "status", auth: AuthNone, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizerAccessToken = tt.accessToken
+			resp, contentType, err := platform.Client.HTTPGetRawWithAuth(tt.auth, "/wxa/get_qrcode")
+			if tt.wantErrcode != 0 {
+				var apiError *ApiError
+				if !errors.As(err, &apiError) || apiError.Errcode != tt.wantErrcode {
+					t.Errorf("HTTPGetRawWithAuth() error = %v, wantErrcode %v", err, tt.wantErrcode)
+				}
+				return
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("HTTPGetRawWithAuth() error = nil, wantErr")
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(resp, tt.wantResp) || contentType != tt.wantContentType {
+				t.Errorf("HTTPGetRawWithAuth() = %q, %q, %v, want %q, %q", resp, contentType, err, tt.wantResp, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestResponseFilter(t *testing.T) {
+	image := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantErrcode int64
+		wantErr     error
+	}{
+		{name: "image", contentType: "image/jpeg", body: image},
+		{name: "stream", contentType: "application/octet-stream", body: []byte(`{"not":"parsed"}`)},
+		{name: "json ok", contentType: "application/json; encoding=utf-8", body: []byte(`{"errcode":0,"errmsg":"ok"}`)},
+		{name: "json error", contentType: "application/json; encoding=utf-8", body: []byte(`{"errcode":40013,"errmsg":"invalid appid"}`), wantErrcode: 40013},
+		{name: "text json error", contentType: "text/plain", body: []byte(`{"errcode":40013,"errmsg":"invalid appid"}`), wantErrcode: 40013},
+		{name: "no content type", body: []byte(` {"errcode":42001,"errmsg":"access_token expired"}`), wantErr: ErrorComponentAccessTokenExpire},
+		{name: "text", contentType: "text/plain; charset=utf-8", body: []byte("plain text")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bytes.NewReader(tt.body)),
+			}
+			if tt.contentType != "" {
+				response.Header.Set("Content-Type", tt.contentType)
+			}
+
+			resp, err := responseFilter(response)
+			switch {
+			case tt.wantErrcode != 0:
+				var apiError *ApiError
+				if !errors.As(err, &apiError) || apiError.Errcode != tt.wantErrcode {
+					t.Errorf("responseFilter() error = %v, wantErrcode %v", err, tt.wantErrcode)
+				}
+			case err != tt.wantErr:
+				t.Errorf("responseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			case !bytes.Equal(resp, tt.body):
+				t.Errorf("responseFilter() = %q, want %q", resp, tt.body)
+			}
+		})
+	}
+}
@@ -17,191 +17,541 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path"
-	"regexp"
+	"path/filepath"
+	"strconv"
 	"strings"
-
-	"github.com/iancoleman/strcase"
 )
 
 func main() {
 	var pkgFlag string
-	flag.StringVar(&pkgFlag, "package", "default", "")
+	var specFlag string
+	var outFlag string
+	var checkFlag bool
+	var coverageFlag string
+	flag.StringVar(&pkgFlag, "package", "default", "生成的包；all 生成全部包、接口列表与接口目录；apilist 输出接口列表；catalog 输出接口目录")
+	flag.StringVar(&specFlag, "spec", "apis.json", "接口描述文件")
+	flag.StringVar(&outFlag, "out", "..", "输出根目录，生成的包位于其下 apis 目录")
+	flag.BoolVar(&checkFlag, "check", false, "检查已生成的代码、接口列表与接口目录是否与描述文件一致")
+	flag.StringVar(&coverageFlag, "coverage", "", "官方接口清单文件 (如 official_apis.txt)，输出尚未实现的接口")
 	flag.Parse()
-	for _, group := range apiConfig {
 
-		if group.Package == pkgFlag {
-			build(group)
+	spec, err := loadSpec(specFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if checkFlag {
+		stale, err := check(spec, outFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+		if stale {
+			fmt.Fprintln(os.Stderr, "generated files are stale, run the generator to update them")
+			os.Exit(1)
+		}
+		return
 	}
 
-	if pkgFlag == "apilist" {
-		apilist()
+	if coverageFlag != "" {
+		official, err := loadOfficialApis(coverageFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		coverage(spec, official, os.Stdout)
+		return
 	}
 
-}
+	switch pkgFlag {
+	case "apilist":
+		fmt.Print(apilist(spec))
+		return
+	case "catalog":
+		content, err := catalog(spec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(content)
+		return
+	}
 
-func apilist() {
-	for _, group := range apiConfig {
-		fmt.Printf("- %s(%s)\n", group.Name, group.Package)
-		for _, api := range group.Apis {
-			split := strings.Split(api.Request, " ")
-			parse, _ := url.Parse(split[1])
+	err = generate(spec, pkgFlag, outFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-			if api.FuncName == "" {
-				api.FuncName = strcase.ToCamel(path.Base(parse.Path))
+/*
+generate 生成 pkg 包并写入 root 目录
+
+pkg 为 all 时生成全部非手写的包，并更新接口列表与接口目录
+*/
+func generate(spec Spec, pkg string, root string) (err error) {
+	if pkg == "all" {
+		for _, group := range spec.Groups {
+			if group.Handwritten {
+				continue
+			}
+			err = buildAndWrite(group, root)
+			if err != nil {
+				return
 			}
+		}
+		err = writeFile(filepath.Join(root, apilistFile), []byte(apilist(spec)))
+		if err != nil {
+			return
+		}
+		content, err := catalog(spec)
+		if err != nil {
+			return err
+		}
+		return writeFile(filepath.Join(root, catalogFile), content)
+	}
+
+	for _, group := range spec.Groups {
+		if group.Package != pkg {
+			continue
+		}
+		if group.Handwritten {
+			return fmt.Errorf("package %s is handwritten, skip", group.Package)
+		}
+		return buildAndWrite(group, root)
+	}
+	return fmt.Errorf("package %s not found in spec", pkg)
+}
+
+func buildAndWrite(group ApiGroup, root string) (err error) {
+	out, err := build(group)
+	if err != nil {
+		return fmt.Errorf("build %s: %v", group.Package, err)
+	}
+	return out.write(root)
+}
 
+func apilist(spec Spec) string {
+	var list strings.Builder
+	for _, group := range spec.Groups {
+		fmt.Fprintf(&list, "- %s(%s)\n", group.Name, group.Package)
+		for _, api := range group.Apis {
 			godocLink := fmt.Sprintf("https://pkg.go.dev/github.com/fastwego/wxopen/apis/%s?tab=doc#%s", group.Package, api.FuncName)
-			fmt.Printf("\t- [%s](%s) \n\t\t- [%s (%s)](%s)\n", api.Name, api.See, api.FuncName, parse.Path, godocLink)
+			fmt.Fprintf(&list, "\t- [%s](%s) \n\t\t- [%s (%s)](%s)\n", api.Name, api.See, api.FuncName, api.Path, godocLink)
+		}
+	}
+	return list.String()
+}
+
+// 接口调用方对应的 ctx 类型与测试用的 mock 实例
+var ctxTypes = map[string][2]string{
+	CtxPlatform:    {"*wxopen.Platform", "test.MockPlatform"},
+	CtxOffiAccount: {"*offiaccount.OffiAccount", "test.MockOffiAccount"},
+	CtxMiniprogram: {"*miniprogram.Miniprogram", "test.MockMiniprogram"},
+}
+
+// 鉴权方式对应的 wxopen.Auth (仅第三方平台 ctx 使用)
+var authExprs = map[string]string{
+	AuthComponent:  "wxopen.AuthComponent",
+	AuthAuthorizer: "wxopen.AuthAuthorizer(appid)",
+	AuthUser:       "wxopen.AuthUser",
+	AuthNone:       "wxopen.AuthNone",
+}
+
+// funcArg 生成方法的参数
+type funcArg struct {
+	Name string
+	Type string
+}
+
+// funcArgs 按接口描述计算方法参数
+func funcArgs(group ApiGroup, api Api) (args []funcArg) {
+	if api.Redirect {
+		if len(api.Query) > 0 {
+			args = append(args, funcArg{"params", "url.Values"})
+		}
+		return
+	}
+
+	args = append(args, funcArg{"ctx", ctxTypes[group.Ctx][0]})
+	if group.Ctx == CtxPlatform && api.Auth == AuthAuthorizer {
+		args = append(args, funcArg{"appid", "string"})
+	}
+	switch {
+	case api.Upload != nil:
+		for _, field := range api.Upload.FileFields {
+			args = append(args, funcArg{field, "string"})
+		}
+		if api.Upload.PayloadField != "" {
+			args = append(args, funcArg{"payload", "[]byte"})
 		}
+		for _, field := range api.Upload.FormFields {
+			args = append(args, funcArg{field, "string"})
+		}
+	case api.Method == "POST":
+		args = append(args, funcArg{"payload", "[]byte"})
+	}
+	if len(api.Query) > 0 {
+		args = append(args, funcArg{"params", "url.Values"})
+	}
+	return
+}
+
+// isFileField 参数是否为上传的文件
+func isFileField(api Api, name string) bool {
+	if api.Upload == nil {
+		return false
+	}
+	for _, field := range api.Upload.FileFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readerFuncArgs 上传方法的 io.Reader 版本参数：每个文件对应 io.Reader 与文件名
+func readerFuncArgs(api Api, args []funcArg) (readerArgs []funcArg) {
+	for _, arg := range args {
+		if isFileField(api, arg.Name) {
+			readerArgs = append(readerArgs, funcArg{arg.Name, "io.Reader"}, funcArg{arg.Name + "Filename", "string"})
+			continue
+		}
+		readerArgs = append(readerArgs, arg)
+	}
+	return
+}
+
+// clientCall 发送请求的语句
+func clientCall(group ApiGroup, api Api, uri string, body string, contentType string) string {
+	method := "HTTPGet"
+	callArgs := uri
+	if api.Method == "POST" {
+		method = "HTTPPost"
+		callArgs += ", " + body + ", " + contentType
+	}
+
+	auth := authExprs[api.Auth]
+	if api.TokenParam != "" {
+		auth = `wxopen.AuthComponentTokenParam("` + api.TokenParam + `")`
 	}
+	if api.Binary {
+		// 二进制响应只支持第三方平台 ctx
+		return "ctx.Client." + method + "RawWithAuth(" + auth + ", " + callArgs + ")"
+	}
+	if group.Ctx == CtxPlatform && (api.Auth != AuthComponent || api.TokenParam != "") {
+		return "ctx.Client." + method + "WithAuth(" + auth + ", " + callArgs + ")"
+	}
+	return "ctx.Client." + method + "(" + callArgs + ")"
+}
+
+// funcResults 方法的返回值：二进制响应额外返回 Content-Type
+func funcResults(api Api) string {
+	if api.Binary {
+		return "resp []byte, contentType string, err error"
+	}
+	return "resp []byte, err error"
+}
+
+// testArgValue 测试中调用方法时参数的取值
+func testArgValue(arg funcArg, mockCtx string) string {
+	switch {
+	case arg.Name == "ctx":
+		return mockCtx
+	case arg.Name == "appid":
+		return `"AUTHORIZER_APPID"`
+	case arg.Type == "[]byte":
+		return `[]byte("{}")`
+	case arg.Type == "url.Values":
+		return "url.Values{}"
+	}
+	return `""`
 }
 
-func build(group ApiGroup) {
+/*
+output 一个包生成的文件，以相对仓库根目录的路径为 key
+
+files 每次生成都会覆盖；fixtures 仅在不存在时写入，便于替换为真实录制的数据
+*/
+type output struct {
+	files    map[string][]byte
+	fixtures map[string][]byte
+}
+
+// build 在内存中生成包代码、测试、示例与 fixture
+func build(group ApiGroup) (out output, err error) {
+	out = output{files: map[string][]byte{}, fixtures: map[string][]byte{}}
+	dir := path.Join("apis", group.Package)
+	pkgName := path.Base(group.Package)
+
+	_MOCK_CTX_ := ctxTypes[group.Ctx][1]
+
 	var funcs []string
 	var consts []string
 	var testFuncs []string
 	var exampleFuncs []string
+	types := newStructBuilder()
+	var typedFuncs []string
 
 	for _, api := range group.Apis {
-		tpl := postFuncTpl
-		_FUNC_NAME_ := ""
-		_GET_PARAMS_ := ""
-		_GET_SUFFIX_PARAMS_ := ""
-		_UPLOAD_ := "media"
-		_FIELD_NAME_ := ""
-		_FIELDS_ := ""
-		_PAYLOAD_ := ""
-		switch {
-		case strings.Contains(api.Request, "GET http"):
-			tpl = getFuncTpl
-		case strings.Contains(api.Request, "POST http"):
-			tpl = postFuncTpl
-		case strings.Contains(api.Request, "POST(@media"):
-			tpl = postUploadFuncTpl
-			_UPLOAD_ = "media"
-
-			pattern := `POST\(@media\|field=(\S+)\) http`
-			reg := regexp.MustCompile(pattern)
-			matched := reg.FindAllStringSubmatch(api.Request, -1)
-			if matched != nil {
-				_FIELD_NAME_ = matched[0][1]
-				_PAYLOAD_ = ", payload []byte"
-			}
-		}
-		if len(api.GetParams) > 0 {
-			_GET_PARAMS_ = `, params url.Values`
-			//if strings.Contains(api.Request, "POST") {
-			//	_GET_PARAMS_ = `, ` + _GET_PARAMS_
-			//}
-			_GET_SUFFIX_PARAMS_ = `+ "?" + params.Encode()`
+		_FUNC_NAME_ := api.FuncName
+		args := funcArgs(group, api)
+
+		signatures := []string{}
+		for _, arg := range args {
+			signatures = append(signatures, arg.Name+" "+arg.Type)
 		}
 
-		split := strings.Split(api.Request, " ")
-		parseUrl, _ := url.Parse(split[1])
+		tpl := funcTpl
+		_URI_ := "api" + _FUNC_NAME_
+		if len(api.Query) > 0 {
+			_URI_ += ` + "?" + params.Encode()`
+		}
+		_BODY_ := ""
+		_READER_FUNC_ := ""
+		switch {
+		case api.Redirect:
+			tpl = redirectFuncTpl
+			parsed, _ := url.Parse(api.Url)
+			_URI_ = strconv.Quote(parsed.Scheme + "://" + parsed.Host + parsed.Path)
+			if len(api.Query) > 0 {
+				_URI_ = strconv.Quote(parsed.Scheme+"://"+parsed.Host+parsed.Path+"?") + " + params.Encode()"
+			}
+			if parsed.Fragment != "" {
+				_URI_ += " + " + strconv.Quote("#"+parsed.Fragment)
+			}
+		case api.Upload != nil:
+			// 打开文件后交给 io.Reader 版本上传
+			_OPEN_ := []string{}
+			readerCallArgs := []string{}
+			for _, arg := range args {
+				if isFileField(api, arg.Name) {
+					_OPEN_ = append(_OPEN_, strings.ReplaceAll(openFileTpl, "_UPLOAD_", arg.Name))
+					readerCallArgs = append(readerCallArgs, arg.Name+"File", "path.Base("+arg.Name+")")
+					continue
+				}
+				readerCallArgs = append(readerCallArgs, arg.Name)
+			}
+			_BODY_ = strings.Join(_OPEN_, "\n") + "\n\treturn " + _FUNC_NAME_ + "FromReader(" + strings.Join(readerCallArgs, ", ") + ")"
 
-		if api.FuncName == "" {
-			_FUNC_NAME_ = strcase.ToCamel(path.Base(parseUrl.Path))
-		} else {
-			_FUNC_NAME_ = api.FuncName
+			_FILES_ := []string{}
+			for _, field := range api.Upload.FileFields {
+				_FILES_ = append(_FILES_, strings.ReplaceAll(uploadFileTpl, "_UPLOAD_", field))
+			}
+			_FIELDS_ := []string{}
+			if api.Upload.PayloadField != "" {
+				field := strings.ReplaceAll(fieldTpl, "_FIELD_NAME_", api.Upload.PayloadField)
+				_FIELDS_ = append(_FIELDS_, strings.ReplaceAll(field, "_FIELD_VALUE_", "string(payload)"))
+			}
+			for _, name := range api.Upload.FormFields {
+				field := strings.ReplaceAll(fieldTpl, "_FIELD_NAME_", name)
+				_FIELDS_ = append(_FIELDS_, strings.ReplaceAll(field, "_FIELD_VALUE_", name))
+			}
+			readerSignatures := []string{}
+			for _, arg := range readerFuncArgs(api, args) {
+				readerSignatures = append(readerSignatures, arg.Name+" "+arg.Type)
+			}
+			_READER_FUNC_ = uploadFuncTpl
+			readerBody := "r"
+			if group.Ctx != CtxPlatform {
+				// 公众号/小程序实例重试时重新发送同一个请求，请求体需要能够重放
+				_READER_FUNC_ = bufferedUploadFuncTpl
+				readerBody = "bytes.NewReader(body.Bytes())"
+			}
+			_READER_FUNC_ = strings.ReplaceAll(_READER_FUNC_, "_PARTS_", strings.Join(append(_FILES_, _FIELDS_...), "\n"))
+			_READER_FUNC_ = strings.ReplaceAll(_READER_FUNC_, "_CALL_", clientCall(group, api, _URI_, readerBody, "m.FormDataContentType()"))
+			_READER_FUNC_ = strings.ReplaceAll(_READER_FUNC_, "_ARGS_", strings.Join(readerSignatures, ", "))
+		default:
+			_BODY_ = "\treturn " + clientCall(group, api, _URI_, "bytes.NewReader(payload)", `"application/json;charset=utf-8"`)
 		}
 
 		tpl = strings.ReplaceAll(tpl, "_TITLE_", api.Name)
 		tpl = strings.ReplaceAll(tpl, "_DESCRIPTION_", api.Description)
-		tpl = strings.ReplaceAll(tpl, "_REQUEST_", api.Request)
+		tpl = strings.ReplaceAll(tpl, "_REQUEST_", api.requestLine())
 		tpl = strings.ReplaceAll(tpl, "_SEE_", api.See)
 		tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", _FUNC_NAME_)
-		tpl = strings.ReplaceAll(tpl, "_UPLOAD_", _UPLOAD_)
-		tpl = strings.ReplaceAll(tpl, "_GET_PARAMS_", _GET_PARAMS_)
-		tpl = strings.ReplaceAll(tpl, "_GET_SUFFIX_PARAMS_", _GET_SUFFIX_PARAMS_)
-		if _FIELD_NAME_ != "" {
-			_FIELDS_ = strings.ReplaceAll(fieldTpl, "_FIELD_NAME_", _FIELD_NAME_)
-		}
-		tpl = strings.ReplaceAll(tpl, "_FIELDS_", _FIELDS_)
-		tpl = strings.ReplaceAll(tpl, "_PAYLOAD_", _PAYLOAD_)
+		tpl = strings.ReplaceAll(tpl, "_ARGS_", strings.Join(signatures, ", "))
+		tpl = strings.ReplaceAll(tpl, "_BODY_", _BODY_)
+		tpl = strings.ReplaceAll(tpl, "_RESULTS_", funcResults(api))
+		tpl = strings.ReplaceAll(tpl, "_URI_", _URI_)
 
 		funcs = append(funcs, tpl)
 
+		if _READER_FUNC_ != "" {
+			tpl = strings.ReplaceAll(_READER_FUNC_, "_TITLE_", api.Name)
+			tpl = strings.ReplaceAll(tpl, "_SEE_", api.See)
+			tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", _FUNC_NAME_)
+			tpl = strings.ReplaceAll(tpl, "_RESULTS_", funcResults(api))
+			funcs = append(funcs, tpl)
+		}
+
+		// 类型化请求 / 响应
+		typedArgs := []string{}
+		typedCallArgs := []string{}
+		typedTestArgs := []string{}
+		if api.Request != nil || api.Response != nil {
+			for _, arg := range args {
+				switch {
+				case arg.Name == "payload" && api.Request != nil:
+					typedArgs = append(typedArgs, "req "+_FUNC_NAME_+"Request")
+					typedTestArgs = append(typedTestArgs, _FUNC_NAME_+"Request{}")
+				default:
+					typedArgs = append(typedArgs, arg.Name+" "+arg.Type)
+					typedTestArgs = append(typedTestArgs, testArgValue(arg, _MOCK_CTX_))
+				}
+				typedCallArgs = append(typedCallArgs, arg.Name)
+			}
+
+			if api.Request != nil {
+				types.build(_FUNC_NAME_+"Request", _FUNC_NAME_, api.Name+"请求参数", api.Request.Fields, true)
+			}
+			if api.Response != nil {
+				types.build(_FUNC_NAME_+"Response", _FUNC_NAME_, api.Name+"响应", api.Response.Fields, false)
+			}
+
+			tpl = typedFuncTpl
+			_MARSHAL_ := ""
+			if api.Request != nil {
+				_MARSHAL_ = marshalTpl
+			}
+			_RESULT_ := "err error"
+			_UNMARSHAL_ := "\t_, err = "
+			if api.Response != nil {
+				_RESULT_ = "result _FUNC_NAME_Response, err error"
+				_UNMARSHAL_ = "\tresp, err := "
+			}
+			_UNMARSHAL_ += "_FUNC_NAME_(" + strings.Join(typedCallArgs, ", ") + ")\n"
+			if api.Response != nil {
+				_UNMARSHAL_ += unmarshalTpl
+			}
+			tpl = strings.ReplaceAll(tpl, "_RESULT_", _RESULT_)
+			tpl = strings.ReplaceAll(tpl, "_MARSHAL_", _MARSHAL_)
+			tpl = strings.ReplaceAll(tpl, "_CALL_", _UNMARSHAL_)
+			tpl = strings.ReplaceAll(tpl, "_TITLE_", api.Name)
+			tpl = strings.ReplaceAll(tpl, "_SEE_", api.See)
+			tpl = strings.ReplaceAll(tpl, "_ARGS_", strings.Join(typedArgs, ", "))
+			tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", _FUNC_NAME_)
+			typedFuncs = append(typedFuncs, tpl)
+
+			// round-trip 测试
+			for _, item := range []struct {
+				kind   string
+				schema *Schema
+			}{{"Request", api.Request}, {"Response", api.Response}} {
+				if item.schema == nil || len(item.schema.Sample) == 0 {
+					continue
+				}
+				tpl = strings.ReplaceAll(roundTripTestTpl, "_TYPE_", _FUNC_NAME_+item.kind)
+				tpl = strings.ReplaceAll(tpl, "_SAMPLE_", sampleLiteral(item.schema.Sample))
+				testFuncs = append(testFuncs, tpl)
+			}
+		}
+
+		if api.Redirect {
+			// 跳转链接无需请求微信服务器
+			continue
+		}
+
 		tpl = strings.ReplaceAll(constTpl, "_FUNC_NAME_", _FUNC_NAME_)
-		tpl = strings.ReplaceAll(tpl, "_API_PATH_", parseUrl.Path)
+		tpl = strings.ReplaceAll(tpl, "_API_PATH_", api.Path)
 
 		consts = append(consts, tpl)
 
 		// TestFunc
-		_TEST_ARGS_STRUCT_ := ""
+		out.fixtures[path.Join(dir, "testdata", _FUNC_NAME_+".json")], err = fixtureContent(api)
+		if err != nil {
+			return
+		}
+
+		paramNames := []string{}
+		testArgs := []string{}
+		for _, arg := range args {
+			paramNames = append(paramNames, "tt.args."+arg.Name)
+			testArgs = append(testArgs, arg.Name+": "+fixtureArgValue(api, arg, _MOCK_CTX_))
+		}
+
+		_EXPIRE_CASE_ := `
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp, fixture.Response}, wantResp: fixture.Response, wantCalls: 2},`
 		switch {
-		case strings.Contains(api.Request, "GET http"):
-			_TEST_ARGS_STRUCT_ = `ctx *wxopen.Platform, ` + _GET_PARAMS_
-		case strings.Contains(api.Request, "POST http"):
-			_TEST_ARGS_STRUCT_ = `ctx *wxopen.Platform, payload []byte`
-			if _GET_PARAMS_ != "" {
-				_TEST_ARGS_STRUCT_ += `,` + _GET_PARAMS_
-			}
-		case strings.Contains(api.Request, "POST(@media"):
-			_TEST_ARGS_STRUCT_ = `ctx *wxopen.Platform, ` + _UPLOAD_ + ` string` + _PAYLOAD_ + _GET_PARAMS_
-		}
-		_TEST_ARGS_STRUCT_ = strings.ReplaceAll(_TEST_ARGS_STRUCT_, ",", "\n")
-
-		_TEST_FUNC_SIGNATURE_ := ""
-		_EXAMPLE_ARGS_STMT_ := ""
-		if strings.TrimSpace(_TEST_ARGS_STRUCT_) != "" {
-			signatures := strings.Split(_TEST_ARGS_STRUCT_, "\n")
-			paramNames := []string{}
-			exampleStmt := []string{}
-			for _, signature := range signatures {
-				signature = strings.TrimSpace(signature)
-				tmp := strings.Split(signature, " ")
-				//fmt.Println(tmp)
-				if len(tmp[0]) > 0 {
-					paramNames = append(paramNames, "tt.args."+tmp[0])
-
-					switch tmp[1] {
-					case `[]byte`:
-						exampleStmt = append(exampleStmt, tmp[0]+" := []byte(\"{}\")")
-					case `string`:
-						exampleStmt = append(exampleStmt, tmp[0]+" := \"\"")
-					case `url.Values`:
-						exampleStmt = append(exampleStmt, tmp[0]+" := url.Values{}")
-					}
+		case api.Upload != nil && group.Ctx == CtxPlatform:
+			// 流式上传的请求体无法重放，令牌过期时不重试
+			_EXPIRE_CASE_ = `
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp}, wantErr: wxopen.ErrorBodyNotReplayable.Error(), wantCalls: 1},`
+		case group.Ctx == CtxPlatform && (api.Auth == AuthUser || api.Auth == AuthNone):
+			// 用户令牌 / 无鉴权接口不会刷新令牌重试
+			_EXPIRE_CASE_ = `
+		{name: "token expire", args: arguments, responses: [][]byte{expiredResp}, wantErr: string(expiredResp), wantCalls: 1},`
+		}
+
+		tpl = testFuncTpl
+		if api.Binary {
+			tpl = binaryTestFuncTpl
+			_EXPIRE_CASE_ = strings.ReplaceAll(_EXPIRE_CASE_, "fixture.Response}, wantResp: fixture.Response", "body}, wantResp: body, wantContentType: fixture.ContentType")
+		}
+		tpl = strings.ReplaceAll(tpl, "_ASSERT_REQUEST_", requestAssertions(group, api))
+		tpl = strings.ReplaceAll(tpl, "_EXPIRE_CASE_", _EXPIRE_CASE_)
+		tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", _FUNC_NAME_)
+		tpl = strings.ReplaceAll(tpl, "_METHOD_", strings.Title(strings.ToLower(api.Method)))
+		tpl = strings.ReplaceAll(tpl, "_TEST_ARGS_STRUCT_", strings.Join(signatures, "\n"))
+		tpl = strings.ReplaceAll(tpl, "_TEST_FUNC_SIGNATURE_", strings.Join(paramNames, ","))
+		tpl = strings.ReplaceAll(tpl, "_TEST_ARGS_", strings.Join(testArgs, ", "))
+		_TYPED_TEST_ := ""
+		if api.Response != nil && len(api.Response.Sample) > 0 {
+			_TYPED_TEST_ = strings.ReplaceAll(typedTestTpl, "_FUNC_NAME_", _FUNC_NAME_)
+			_TYPED_TEST_ = strings.ReplaceAll(_TYPED_TEST_, "_TYPED_TEST_ARGS_", strings.Join(typedTestArgs, ", "))
+			_TYPED_TEST_ = strings.ReplaceAll(_TYPED_TEST_, "_SAMPLE_", sampleLiteral(api.Response.Sample))
+		}
+		if api.Upload != nil {
+			openErrorArgs := []string{}
+			readerErrorArgs := []string{}
+			readerArgs := []string{}
+			for _, arg := range args {
+				if isFileField(api, arg.Name) {
+					openErrorArgs = append(openErrorArgs, `"testdata/not_exist"`)
+					readerErrorArgs = append(readerErrorArgs, "test.ErrorReader{Err: readErr}", `"`+_FUNC_NAME_+`.json"`)
+					readerArgs = append(readerArgs, `strings.NewReader("`+arg.Name+`")`, `"`+_FUNC_NAME_+`.json"`)
+					continue
 				}
+				value := fixtureArgValue(api, arg, _MOCK_CTX_)
+				openErrorArgs = append(openErrorArgs, value)
+				readerErrorArgs = append(readerErrorArgs, value)
+				readerArgs = append(readerArgs, value)
 			}
-			_TEST_FUNC_SIGNATURE_ = strings.Join(paramNames, ",")
-			_EXAMPLE_ARGS_STMT_ = strings.Join(exampleStmt, "\n")
+			uploadTest := strings.ReplaceAll(uploadTestTpl, "_FUNC_NAME_", _FUNC_NAME_)
+			uploadTest = strings.ReplaceAll(uploadTest, "_OPEN_ERROR_ARGS_", strings.Join(openErrorArgs, ", "))
+			uploadTest = strings.ReplaceAll(uploadTest, "_READER_ERROR_ARGS_", strings.Join(readerErrorArgs, ", "))
+			uploadTest = strings.ReplaceAll(uploadTest, "_READER_ARGS_", strings.Join(readerArgs, ", "))
+			_TYPED_TEST_ += uploadTest
 		}
-
-		tpl = strings.ReplaceAll(testFuncTpl, "_FUNC_NAME_", _FUNC_NAME_)
-		tpl = strings.ReplaceAll(tpl, "_TEST_ARGS_STRUCT_", _TEST_ARGS_STRUCT_)
-		tpl = strings.ReplaceAll(tpl, "_TEST_FUNC_SIGNATURE_", _TEST_FUNC_SIGNATURE_)
+		tpl = strings.ReplaceAll(tpl, "_TYPED_TEST_", _TYPED_TEST_)
 		testFuncs = append(testFuncs, tpl)
 
 		//Example
-		tpl = strings.ReplaceAll(exampleFuncTpl, "_FUNC_NAME_", _FUNC_NAME_)
-		tpl = strings.ReplaceAll(tpl, "_PACKAGE_", path.Base(group.Package))
-		tpl = strings.ReplaceAll(tpl, "_TEST_FUNC_SIGNATURE_", strings.ReplaceAll(_TEST_FUNC_SIGNATURE_, "tt.args.", ""))
-		tpl = strings.ReplaceAll(tpl, "_EXAMPLE_ARGS_STMT_", _EXAMPLE_ARGS_STMT_)
-		exampleFuncs = append(exampleFuncs, tpl)
+		apiExamples, err := examples(group, api, args, _MOCK_CTX_)
+		if err != nil {
+			return out, err
+		}
+		exampleFuncs = append(exampleFuncs, apiExamples...)
 
 	}
 
-	fileContent := fmt.Sprintf(fileTpl, path.Base(group.Package), group.Name, path.Base(group.Package), strings.Join(consts, ``), strings.Join(funcs, ``))
-	filename := "./../apis/" + group.Package + "/" + path.Base(group.Package) + ".go"
-	_ = os.MkdirAll(path.Dir(filename), 0644)
-	ioutil.WriteFile(filename, []byte(fileContent), 0644)
-
-	// output Test
-	testFileContent := fmt.Sprintf(testFileTpl, path.Base(group.Package), strings.Join(testFuncs, ``))
-	//fmt.Println(testFileContent)
-	ioutil.WriteFile("./../apis/"+group.Package+"/"+path.Base(group.Package)+"_test.go", []byte(testFileContent), 0644)
-
-	// output example
-	exampleFileContent := fmt.Sprintf(exampleFileTpl, path.Base(group.Package), strings.Join(exampleFuncs, ``))
-	//fmt.Println(testFileContent)
-	ioutil.WriteFile("./../apis/"+group.Package+"/example_"+path.Base(group.Package)+"_test.go", []byte(exampleFileContent), 0644)
-
+	sources := map[string]string{
+		pkgName + ".go":                   fmt.Sprintf(fileTpl, pkgName, group.Name, pkgName, strings.Join(consts, ``), strings.Join(funcs, ``)+strings.Join(typedFuncs, ``)+types.String()),
+		pkgName + "_test.go":              fmt.Sprintf(testFileTpl, pkgName, strings.Join(testFuncs, "\n")),
+		"example_" + pkgName + "_test.go": fmt.Sprintf(exampleFileTpl, pkgName, strings.Join(exampleFuncs, ``)),
+	}
+	for name, source := range sources {
+		filename := path.Join(dir, name)
+		out.files[filename], err = formatSource(filename, group.Package, []byte(source))
+		if err != nil {
+			return
+		}
+	}
+	return
 }
 
 var constTpl = `
@@ -216,49 +566,106 @@ See: _SEE_
 
 _REQUEST_
 */`
-var postFuncTpl = commentTpl + `
-func _FUNC_NAME_(ctx *wxopen.Platform, payload []byte_GET_PARAMS_) (resp []byte, err error) {
-	return ctx.Client.HTTPPost(api_FUNC_NAME__GET_SUFFIX_PARAMS_, bytes.NewReader(payload), "application/json;charset=utf-8")
+var funcTpl = commentTpl + `
+func _FUNC_NAME_(_ARGS_) (_RESULTS_) {
+_BODY_
 }
 `
-var getFuncTpl = commentTpl + `
-func _FUNC_NAME_(ctx *wxopen.Platform_GET_PARAMS_) (resp []byte, err error) {
-	return ctx.Client.HTTPGet(api_FUNC_NAME__GET_SUFFIX_PARAMS_)
+var redirectFuncTpl = commentTpl + `
+func _FUNC_NAME_(_ARGS_) (uri string) {
+	return _URI_
 }
 `
-var postUploadFuncTpl = commentTpl + `
-func _FUNC_NAME_(ctx *wxopen.Platform, _UPLOAD_ string_PAYLOAD__GET_PARAMS_) (resp []byte, err error) {
+var openFileTpl = `	_UPLOAD_File, err := os.Open(_UPLOAD_)
+	if err != nil {
+		return
+	}
+	defer _UPLOAD_File.Close()
+`
+
+var uploadFuncTpl = `
+/*
+_FUNC_NAME_FromReader _TITLE_ (从 io.Reader 读取上传的文件)
+
+文件内容以流式写入请求体，读取出错时请求返回该错误
+
+请求体无法重放，access_token 过期或系统繁忙时不会重试，返回 wxopen.ErrorBodyNotReplayable
+
+See: _SEE_
+*/
+func _FUNC_NAME_FromReader(_ARGS_) (_RESULTS_) {
 	r, w := io.Pipe()
+	// 请求未读取请求体就返回（如获取 access_token 失败）时，关闭读取端让写入的 goroutine 退出
+	defer r.Close()
 	m := multipart.NewWriter(w)
 	go func() {
-		defer w.Close()
-		defer m.Close()
+		// 写入出错时通过 CloseWithError 传递给读取请求体的一方
+		_ = w.CloseWithError(func() (err error) {
+			var part io.Writer
+_PARTS_			return m.Close()
+		}())
+	}()
+	return _CALL_
+}
+`
 
-		part, err := m.CreateFormFile("_UPLOAD_", path.Base(_UPLOAD_))
-		if err != nil {
-			return
-		}
-		file, err := os.Open(_UPLOAD_)
-		if err != nil {
-			return
-		}
-		defer file.Close()
-		if _, err = io.Copy(part, file); err != nil {
-			return
-		}
+var bufferedUploadFuncTpl = `
+/*
+_FUNC_NAME_FromReader _TITLE_ (从 io.Reader 读取上传的文件)
 
-		_FIELDS_
-	}()
-	return ctx.Client.HTTPPost(api_FUNC_NAME__GET_SUFFIX_PARAMS_, r, m.FormDataContentType())
+文件内容先全部读入内存组成请求体，读取出错时返回该错误，不发送请求
+
+access_token 过期或系统繁忙时重试会重新发送完整的请求体
+
+See: _SEE_
+*/
+func _FUNC_NAME_FromReader(_ARGS_) (_RESULTS_) {
+	body := &bytes.Buffer{}
+	m := multipart.NewWriter(body)
+	var part io.Writer
+_PARTS_	if err = m.Close(); err != nil {
+		return
+	}
+	return _CALL_
 }
 `
 
-var fieldTpl = `
-		// field
-		err = m.WriteField("_FIELD_NAME_", string(payload))
-		if err != nil {
-			return
-		}
+var uploadFileTpl = `			part, err = m.CreateFormFile("_UPLOAD_", _UPLOAD_Filename)
+			if err != nil {
+				return
+			}
+			if _, err = io.Copy(part, _UPLOAD_); err != nil {
+				return
+			}
+`
+
+var typedFuncTpl = `
+/*
+_FUNC_NAME_Typed _TITLE_ (类型化请求 / 响应)
+
+See: _SEE_
+*/
+func _FUNC_NAME_Typed(_ARGS_) (_RESULT_) {
+_MARSHAL__CALL_	return
+}
+`
+
+var marshalTpl = `	payload, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+`
+
+var unmarshalTpl = `	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &result)
+`
+
+var fieldTpl = `			if err = m.WriteField("_FIELD_NAME_", _FIELD_VALUE_); err != nil {
+				return
+			}
 `
 
 var fileTpl = `// Package %s %s
@@ -271,6 +678,12 @@ const (
 
 var testFileTpl = `package %s
 
+// 模拟接口错误与令牌过期的响应
+var (
+	errcodeResp = []byte("{\"errcode\":40013,\"errmsg\":\"invalid appid\"}")
+	expiredResp = []byte("{\"errcode\":42001,\"errmsg\":\"access_token expired\"}")
+)
+
 func TestMain(m *testing.M) {
 	test.Setup()
 	os.Exit(m.Run())
@@ -281,38 +694,211 @@ func TestMain(m *testing.M) {
 
 var testFuncTpl = `
 func Test_FUNC_NAME_(t *testing.T) {
-	mockResp := map[string][]byte{
-		"case1": []byte("{\"errcode\":0,\"errmsg\":\"ok\"}"),
+	fixture, err := test.LoadFixture("testdata/_FUNC_NAME_.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
 	}
-	var resp []byte
-	test.MockSvrHandler.HandleFunc(api_FUNC_NAME_, func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(resp))
-	})
+	mock := test.HandleMockApi(api_FUNC_NAME_)
 
 	type args struct {
 		_TEST_ARGS_STRUCT_
 	}
+	arguments := args{_TEST_ARGS_}
 	tests := []struct {
-		name     string
-		args     args
-		wantResp []byte
-		wantErr  bool
+		name      string
+		args      args
+		responses [][]byte
+		wantResp  []byte
+		wantErr   string
+		wantCalls int
 	}{
-		{name: "case1", args: args{ctx: test.MockPlatform}, wantResp: mockResp["case1"], wantErr: false},
+		{name: "fixture", args: arguments, responses: [][]byte{fixture.Response}, wantResp: fixture.Response, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},_EXPIRE_CASE_
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp = mockResp[tt.name]
+			mock.Reset(tt.responses...)
 			gotResp, err := _FUNC_NAME_(_TEST_FUNC_SIGNATURE_)
-			//fmt.Println(string(gotResp), err)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("_FUNC_NAME_() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("_FUNC_NAME_() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("_FUNC_NAME_() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) {
+				t.Errorf("_FUNC_NAME_() gotResp = %s, want %s", gotResp, tt.wantResp)
+			}
+` + requestsCheckTpl + `
+_TYPED_TEST_}`
+
+var binaryTestFuncTpl = `
+func Test_FUNC_NAME_(t *testing.T) {
+	fixture, err := test.LoadFixture("testdata/_FUNC_NAME_.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	body, err := fixture.ResponseBody()
+	if err != nil {
+		t.Fatalf("ResponseBody() error = %v", err)
+	}
+	mock := test.HandleMockApi(api_FUNC_NAME_)
+
+	type args struct {
+		_TEST_ARGS_STRUCT_
+	}
+	arguments := args{_TEST_ARGS_}
+	tests := []struct {
+		name            string
+		args            args
+		responses       [][]byte
+		wantResp        []byte
+		wantContentType string
+		wantErr         string
+		wantCalls       int
+	}{
+		{name: "fixture", args: arguments, responses: [][]byte{body}, wantResp: body, wantContentType: fixture.ContentType, wantCalls: 1},
+		{name: "errcode", args: arguments, responses: [][]byte{errcodeResp}, wantErr: string(errcodeResp), wantCalls: 1},_EXPIRE_CASE_
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.Reset(tt.responses...)
+			gotResp, gotContentType, err := _FUNC_NAME_(_TEST_FUNC_SIGNATURE_)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("_FUNC_NAME_() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("_FUNC_NAME_() error = %v", err)
+			} else if !bytes.Equal(gotResp, tt.wantResp) || gotContentType != tt.wantContentType {
+				t.Errorf("_FUNC_NAME_() = %q, %q, want %q, %q", gotResp, gotContentType, tt.wantResp, tt.wantContentType)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("_FUNC_NAME_() gotResp = %v, want %v", gotResp, tt.wantResp)
+` + requestsCheckTpl + `
+}`
+
+var requestsCheckTpl = `
+			requests := mock.Requests()
+			if len(requests) != tt.wantCalls {
+				t.Fatalf("_FUNC_NAME_() calls = %d, want %d", len(requests), tt.wantCalls)
 			}
+			for _, req := range requests {
+				if req.Method != http.Method_METHOD_ || req.Path != api_FUNC_NAME_ {
+					t.Errorf("_FUNC_NAME_() request = %s %s, want %s %s", req.Method, req.Path, http.Method_METHOD_, api_FUNC_NAME_)
+				}
+_ASSERT_REQUEST_			}
 		})
+	}`
+
+var typedTestTpl = `
+	t.Run("typed", func(t *testing.T) {
+		resp := []byte(_SAMPLE_)
+		mock.Reset(resp)
+		var want _FUNC_NAME_Response
+		_ = json.Unmarshal(resp, &want)
+
+		got, err := _FUNC_NAME_Typed(_TYPED_TEST_ARGS_)
+		if err != nil {
+			t.Errorf("_FUNC_NAME_Typed() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("_FUNC_NAME_Typed() got = %v, want %v", got, want)
+		}
+	})
+`
+
+var uploadTestTpl = `
+	t.Run("open error", func(t *testing.T) {
+		mock.Reset(fixture.Response)
+		_, err := _FUNC_NAME_(_OPEN_ERROR_ARGS_)
+		if !os.IsNotExist(err) {
+			t.Errorf("_FUNC_NAME_() error = %v, want not exist", err)
+		}
+		if calls := len(mock.Requests()); calls != 0 {
+			t.Errorf("_FUNC_NAME_() calls = %d, want 0", calls)
+		}
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		mock.Reset(fixture.Response)
+		readErr := errors.New("read error")
+		_, err := _FUNC_NAME_FromReader(_READER_ERROR_ARGS_)
+		if !errors.Is(err, readErr) {
+			t.Errorf("_FUNC_NAME_FromReader() error = %v, want %v", err, readErr)
+		}
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		mock.Reset(fixture.Response)
+		tokenErr := errors.New("token error")
+		restore := test.FailAccessToken(tokenErr)
+		defer restore()
+
+		goroutines := runtime.NumGoroutine()
+		for i := 0; i < 10; i++ {
+			_, err := _FUNC_NAME_FromReader(_READER_ARGS_)
+			if !errors.Is(err, tokenErr) {
+				t.Fatalf("_FUNC_NAME_FromReader() error = %v, want %v", err, tokenErr)
+			}
+		}
+		if !test.GoroutinesSettled(goroutines) {
+			t.Errorf("_FUNC_NAME_FromReader() goroutines = %d, want <= %d", runtime.NumGoroutine(), goroutines)
+		}
+	})
+`
+
+var tokenAssertTpl = `				if got := req.Query.Get("_TOKEN_PARAM_"); got != "_TOKEN_" {
+					t.Errorf("_FUNC_NAME_() _TOKEN_PARAM_ = %q, want %q", got, "_TOKEN_")
+				}
+`
+
+var noTokenAssertTpl = `				if req.Query.Get("component_access_token") != "" {
+					t.Errorf("_FUNC_NAME_() should not send component_access_token")
+				}
+`
+
+var queryAssertTpl = `				for name := range fixture.Query {
+					if got, want := req.Query.Get(name), fixture.Query.Get(name); got != want {
+						t.Errorf("_FUNC_NAME_() query %s = %q, want %q", name, got, want)
+					}
+				}
+`
+
+var bodyAssertTpl = `				if !bytes.Equal(req.Body, fixture.Body) {
+					t.Errorf("_FUNC_NAME_() body = %s, want %s", req.Body, fixture.Body)
+				}
+`
+
+var uploadAssertTpl = `				if files, err := req.FormFiles(); err != nil || files["_UPLOAD_"] != "_FUNC_NAME_.json" {
+					t.Errorf("_FUNC_NAME_() upload files = %v, error = %v", files, err)
+				}
+`
+
+var formValueAssertTpl = `				if values, err := req.FormValues(); err != nil || values.Get("_FIELD_NAME_") != _FIELD_VALUE_ {
+					t.Errorf("_FUNC_NAME_() form _FIELD_NAME_ = %q, error = %v", values.Get("_FIELD_NAME_"), err)
+				}
+`
+
+var roundTripTestTpl = `
+func Test_TYPE_RoundTrip(t *testing.T) {
+	sample := []byte(_SAMPLE_)
+
+	var got _TYPE_
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("decode _TYPE_ error = %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("encode _TYPE_ error = %v", err)
+	}
+	var again _TYPE_
+	if err = json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decode _TYPE_ error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("_TYPE_ round-trip got = %v, want %v", again, got)
 	}
 }`
 
@@ -322,12 +908,19 @@ var exampleFileTpl = `package %s_test
 `
 var exampleFuncTpl = `
 func Example_FUNC_NAME_() {
-	var ctx *wxopen.Platform
+	// 示例使用模拟服务器，实际调用时 ctx 为平台 / 授权方实例
+	test.Setup()
+	test.HandleMockApi("_API_PATH_").Reset([]byte(_MOCK_RESP_))
 
 	_EXAMPLE_ARGS_STMT_
-	resp, err := _PACKAGE_._FUNC_NAME_(_TEST_FUNC_SIGNATURE_)
+	resp, err := _PACKAGE_._FUNC_NAME_(_EXAMPLE_ARGS_)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	_PRINT_
 
-	fmt.Println(resp, err)
+	// Output:
+_OUTPUT_
 }
-
 `
@@ -0,0 +1,118 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const catalogFile = "doc/apicatalog.json"
+
+// catalogEntry 接口目录中的一个接口
+type catalogEntry struct {
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	Name     string `json:"name"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Auth     string `json:"auth"`
+	Doc      string `json:"doc"`
+}
+
+// catalog 机器可读的接口目录，与接口列表包含相同的接口
+func catalog(spec Spec) (content []byte, err error) {
+	entries := []catalogEntry{}
+	for _, group := range spec.Groups {
+		for _, api := range group.Apis {
+			entries = append(entries, catalogEntry{
+				Package:  group.Package,
+				Function: api.FuncName,
+				Name:     api.Name,
+				Method:   api.Method,
+				Path:     api.Path,
+				Auth:     api.Auth,
+				Doc:      api.See,
+			})
+		}
+	}
+
+	content, err = json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+	return append(content, '\n'), nil
+}
+
+// officialApi 官方接口清单中的一行
+type officialApi struct {
+	Path string
+	Name string
+}
+
+/*
+loadOfficialApis 读取官方接口清单
+
+每行为接口 path 与可选的名称，以空白分隔；# 开头的行为注释
+*/
+func loadOfficialApis(filename string) (apis []officialApi, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if !strings.HasPrefix(fields[0], "/") {
+			return nil, fmt.Errorf("%s:%d: path must start with /", filename, lineNo)
+		}
+		apis = append(apis, officialApi{Path: fields[0], Name: strings.Join(fields[1:], " ")})
+	}
+	return apis, scanner.Err()
+}
+
+/*
+coverage 对比接口目录与官方接口清单，输出未实现的接口
+
+返回未实现的接口数量
+*/
+func coverage(spec Spec, official []officialApi, w io.Writer) (missing int) {
+	implemented := map[string]bool{}
+	for _, group := range spec.Groups {
+		for _, api := range group.Apis {
+			implemented[api.Path] = true
+		}
+	}
+
+	for _, api := range official {
+		if implemented[api.Path] {
+			continue
+		}
+		missing++
+		fmt.Fprintf(w, "%s\t%s\n", api.Path, api.Name)
+	}
+	fmt.Fprintf(w, "implemented %d/%d official apis, %d missing\n", len(official)-missing, len(official), missing)
+	return
+}
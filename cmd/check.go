@@ -0,0 +1,199 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const apilistFile = "doc/apilist.md"
+
+// write 将生成的文件写入 root 目录，已存在的 fixture 不覆盖
+func (out output) write(root string) (err error) {
+	for _, name := range sortedKeys(out.files) {
+		err = writeFile(filepath.Join(root, name), out.files[name])
+		if err != nil {
+			return
+		}
+	}
+
+	for _, name := range sortedKeys(out.fixtures) {
+		filename := filepath.Join(root, name)
+		if _, err = os.Stat(filename); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return
+		}
+		err = writeFile(filename, out.fixtures[name])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func writeFile(filename string, content []byte) (err error) {
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return
+	}
+	return ioutil.WriteFile(filename, content, 0644)
+}
+
+/*
+check 在内存中重新生成全部包、接口列表与接口目录，与 root 目录中的文件比较
+
+不一致时输出 diff 并返回 stale = true；缺少 fixture，或 apis 目录中有不再生成的文件 (如已从描述文件删除的包) 也视为不一致
+*/
+func check(spec Spec, root string) (stale bool, err error) {
+	catalogContent, err := catalog(spec)
+	if err != nil {
+		return
+	}
+	want := map[string][]byte{
+		apilistFile: []byte(apilist(spec)),
+		catalogFile: catalogContent,
+	}
+	var fixtures []string
+	for _, group := range spec.Groups {
+		if group.Handwritten {
+			continue
+		}
+		out, err := build(group)
+		if err != nil {
+			return false, err
+		}
+		for name, content := range out.files {
+			want[name] = content
+		}
+		for name := range out.fixtures {
+			fixtures = append(fixtures, name)
+		}
+	}
+
+	for _, name := range sortedKeys(want) {
+		got, err := ioutil.ReadFile(filepath.Join(root, name))
+		if err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		if diff := unifiedDiff("a/"+name, "b/"+name, string(got), string(want[name])); diff != "" {
+			stale = true
+			fmt.Print(diff)
+		}
+	}
+
+	sort.Strings(fixtures)
+	for _, name := range fixtures {
+		if _, err := os.Stat(filepath.Join(root, name)); os.IsNotExist(err) {
+			stale = true
+			fmt.Printf("missing fixture %s\n", name)
+		} else if err != nil {
+			return false, err
+		}
+	}
+
+	extra, err := staleFiles(root, want, fixtures)
+	if err != nil {
+		return
+	}
+	for _, name := range extra {
+		stale = true
+		fmt.Printf("stale file %s\n", name)
+	}
+	return
+}
+
+/*
+staleFiles 找出 root/apis 目录中不在本次生成结果内的生成文件
+
+带有生成标记的 go 文件视为生成文件；生成包 testdata 目录中不属于本次 fixture 的文件同样列出
+*/
+func staleFiles(root string, want map[string][]byte, fixtures []string) (names []string, err error) {
+	generated := map[string]bool{}
+	for _, name := range fixtures {
+		generated[name] = true
+	}
+	// 生成包目录：本次生成的包与带有生成标记文件的目录
+	pkgDirs := map[string]bool{}
+	for name := range want {
+		if strings.HasPrefix(name, "apis/") {
+			pkgDirs[path.Dir(name)] = true
+		}
+	}
+
+	var testdata []string
+	err = filepath.Walk(filepath.Join(root, "apis"), func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filename)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		if path.Base(path.Dir(name)) == "testdata" {
+			testdata = append(testdata, name)
+			return nil
+		}
+		if path.Ext(name) != ".go" {
+			return nil
+		}
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(content, []byte("\n"+generatedMarker+"\n")) {
+			return nil
+		}
+		pkgDirs[path.Dir(name)] = true
+		if _, ok := want[name]; !ok {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	for _, name := range testdata {
+		if pkgDirs[path.Dir(path.Dir(name))] && !generated[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+func sortedKeys(files map[string][]byte) (names []string) {
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
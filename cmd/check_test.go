@@ -0,0 +1,94 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStaleFiles(t *testing.T) {
+	out, err := build(validGroup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixtures []string
+	for name := range out.fixtures {
+		fixtures = append(fixtures, name)
+	}
+
+	tests := []struct {
+		name  string
+		extra map[string]string
+		want  []string
+	}{
+		{
+			name: "up to date",
+		},
+		{
+			name: "removed package",
+			extra: map[string]string{
+				"apis/removed/removed.go":                 "package removed\n\n" + generatedMarker + "\n",
+				"apis/removed/testdata/GetSomething.json": "{}",
+			},
+			want: []string{"apis/removed/removed.go", "apis/removed/testdata/GetSomething.json"},
+		},
+		{
+			name: "removed api fixture",
+			extra: map[string]string{
+				"apis/auth/testdata/RemovedApi.json": "{}",
+			},
+			want: []string{"apis/auth/testdata/RemovedApi.json"},
+		},
+		{
+			name: "handwritten package",
+			extra: map[string]string{
+				"apis/handwritten/handwritten.go":        "package handwritten\n",
+				"apis/handwritten/testdata/fixture.json": "{}",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "wxopen-check")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(root)
+
+			err = out.write(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.extra {
+				err = writeFile(filepath.Join(root, name), []byte(content))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := staleFiles(root, out.files, fixtures)
+			if err != nil {
+				t.Fatalf("staleFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("staleFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3 // 变更前后保留的上下文行数
+
+// diffOp 一行的差异：' ' 相同，'-' 仅旧文件有，'+' 仅新文件有
+type diffOp struct {
+	kind byte
+	line string
+}
+
+/*
+unifiedDiff 输出 old 与 new 的行级 unified diff
+
+内容相同时返回空字符串
+*/
+func unifiedDiff(oldName string, newName string, old string, new string) string {
+	if old == new {
+		return ""
+	}
+
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	// 按变更分块，相邻变更的上下文重叠时合并
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		to := start
+		for last := start; to < len(ops); to++ {
+			if ops[to].kind != ' ' {
+				last = to
+			} else if to-last > 2*diffContext {
+				break
+			}
+		}
+		// 去掉末尾多余的上下文
+		for to > start && ops[to-1].kind == ' ' && countTrailing(ops[:to]) > diffContext {
+			to--
+		}
+
+		oldStart, newStart := lineNumbers(ops[:from])
+		oldCount, newCount := lineNumbers(ops[from:to])
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart+1, oldCount, newStart+1, newCount)
+		for _, op := range ops[from:to] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// diffLines 由最长公共子序列计算行级差异
+func diffLines(old []string, new []string) (ops []diffOp) {
+	// lcs[i][j] 为 old[i:] 与 new[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			ops = append(ops, diffOp{' ', old[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', old[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', new[j]})
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		ops = append(ops, diffOp{'-', old[i]})
+	}
+	for ; j < len(new); j++ {
+		ops = append(ops, diffOp{'+', new[j]})
+	}
+	return
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineNumbers ops 覆盖的旧文件 / 新文件行数
+func lineNumbers(ops []diffOp) (old int, new int) {
+	for _, op := range ops {
+		if op.kind != '+' {
+			old++
+		}
+		if op.kind != '-' {
+			new++
+		}
+	}
+	return
+}
+
+// countTrailing ops 末尾连续相同行的数量
+func countTrailing(ops []diffOp) (n int) {
+	for i := len(ops) - 1; i >= 0 && ops[i].kind == ' '; i-- {
+		n++
+	}
+	return
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numberedLines 生成 l1 到 ln 的多行文本，replace 中的行号替换为大写
+func numberedLines(n int, replace ...int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		line := fmt.Sprintf("l%d", i)
+		for _, r := range replace {
+			if r == i {
+				line = fmt.Sprintf("L%d", i)
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  numberedLines(3),
+			new:  numberedLines(3),
+			want: "",
+		},
+		{
+			name: "change",
+			old:  "a\nb\nc\nd\ne\nf\ng\nh\n",
+			new:  "a\nb\nc\nd\nE\nf\ng\nh\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n",
+		},
+		{
+			name: "append",
+			old:  "a\nb\n",
+			new:  "a\nb\nc\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n b\n+c\n",
+		},
+		{
+			name: "separate hunks",
+			old:  numberedLines(20),
+			new:  numberedLines(20, 2, 18),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n l1\n-l2\n+L2\n l3\n l4\n l5\n" +
+				"@@ -15,6 +15,6 @@\n l15\n l16\n l17\n-l18\n+L18\n l19\n l20\n",
+		},
+		{
+			name: "merged hunks",
+			old:  numberedLines(10),
+			new:  numberedLines(10, 3, 6),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,9 +1,9 @@\n l1\n l2\n-l3\n+L3\n l4\n l5\n-l6\n+L6\n l7\n l8\n l9\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", tt.old, tt.new); got != tt.want {
+				t.Errorf("unifiedDiff() got =\n%s\nwant =\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+)
+
+/*
+examples 接口的 godoc 示例：在模拟服务器上调用方法，并以 // Output: 校验输出
+
+上传接口额外生成 io.Reader 版本的示例
+*/
+func examples(group ApiGroup, api Api, args []funcArg, mockCtx string) (funcs []string, err error) {
+	mockResp, output, err := exampleResponse(api)
+	if err != nil {
+		return
+	}
+
+	var stmts, callArgs []string
+	for _, arg := range args {
+		callArgs = append(callArgs, arg.Name)
+		if arg.Name == "ctx" {
+			callArgs[len(callArgs)-1] = mockCtx
+			continue
+		}
+		stmts = append(stmts, exampleArgStmt(api, arg)...)
+	}
+	funcs = append(funcs, exampleFunc(group, api, api.FuncName, stmts, callArgs, mockResp, output))
+
+	if api.Upload == nil {
+		return
+	}
+	stmts, callArgs = nil, nil
+	for _, arg := range readerFuncArgs(api, args) {
+		callArgs = append(callArgs, arg.Name)
+		switch {
+		case arg.Name == "ctx":
+			callArgs[len(callArgs)-1] = mockCtx
+		case arg.Type == "io.Reader":
+			stmts = append(stmts, arg.Name+` := strings.NewReader("`+strings.ToUpper(arg.Name)+`")`)
+		case isFileField(api, strings.TrimSuffix(arg.Name, "Filename")):
+			callArgs[len(callArgs)-1] = `"` + strings.TrimSuffix(arg.Name, "Filename") + `.jpg"`
+		default:
+			stmts = append(stmts, exampleArgStmt(api, arg)...)
+		}
+	}
+	funcs = append(funcs, exampleFunc(group, api, api.FuncName+"FromReader", stmts, callArgs, mockResp, output))
+	return
+}
+
+// exampleResponse 模拟服务器的响应与示例的输出
+func exampleResponse(api Api) (mockResp string, output []string, err error) {
+	if api.Binary {
+		mockResp = strconv.Quote(string(sampleImage))
+		output = []string{"image/jpeg " + strconv.Itoa(len(sampleImage))}
+		return
+	}
+
+	sample := []byte(`{"errcode":0,"errmsg":"ok"}`)
+	if api.Response != nil && len(api.Response.Sample) > 0 {
+		// 压缩为一行，与输出逐行比较
+		var compact bytes.Buffer
+		if err = json.Compact(&compact, api.Response.Sample); err != nil {
+			return
+		}
+		sample = compact.Bytes()
+	}
+	return sampleLiteral(sample), []string{string(sample)}, nil
+}
+
+// exampleArgStmt 示例中定义参数的语句：payload 取请求示例，其他参数取参数名大写
+func exampleArgStmt(api Api, arg funcArg) []string {
+	switch {
+	case arg.Name == "appid":
+		return []string{`appid := "AUTHORIZER_APPID"`}
+	case arg.Name == "payload":
+		sample := []byte(`{}`)
+		if api.Request != nil && len(api.Request.Sample) > 0 {
+			sample = api.Request.Sample
+		}
+		return []string{"payload := []byte(" + sampleLiteral(sample) + ")"}
+	case arg.Name == "params":
+		stmts := []string{"params := url.Values{}"}
+		for _, param := range api.Query {
+			stmts = append(stmts, `params.Add("`+param.Name+`", "`+strings.ToUpper(param.Name)+`")`)
+		}
+		return stmts
+	case isFileField(api, arg.Name):
+		// 示例目录中一定存在的文件
+		return []string{arg.Name + ` := "testdata/` + api.FuncName + `.json"`}
+	}
+	return []string{arg.Name + ` := "` + strings.ToUpper(arg.Name) + `"`}
+}
+
+func exampleFunc(group ApiGroup, api Api, funcName string, stmts []string, callArgs []string, mockResp string, output []string) string {
+	tpl := exampleFuncTpl
+	_PRINT_ := `fmt.Printf("%s\n", resp)`
+	if api.Binary {
+		tpl = strings.ReplaceAll(tpl, "resp, err :=", "resp, contentType, err :=")
+		_PRINT_ = `fmt.Println(contentType, len(resp))`
+	}
+	tpl = strings.ReplaceAll(tpl, "_FUNC_NAME_", funcName)
+	tpl = strings.ReplaceAll(tpl, "_PACKAGE_", path.Base(group.Package))
+	tpl = strings.ReplaceAll(tpl, "_API_PATH_", api.Path)
+	tpl = strings.ReplaceAll(tpl, "_MOCK_RESP_", mockResp)
+	tpl = strings.ReplaceAll(tpl, "_EXAMPLE_ARGS_STMT_", strings.Join(stmts, "\n"))
+	tpl = strings.ReplaceAll(tpl, "_EXAMPLE_ARGS_", strings.Join(callArgs, ", "))
+	tpl = strings.ReplaceAll(tpl, "_PRINT_", _PRINT_)
+	tpl = strings.ReplaceAll(tpl, "_OUTPUT_", "\t// "+strings.Join(output, "\n\t// "))
+	return tpl
+}
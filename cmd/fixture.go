@@ -0,0 +1,142 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// fixture 与 test.Fixture 格式一致
+type fixture struct {
+	Query       url.Values      `json:"query,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// 二进制接口 fixture 的默认响应：jpeg 文件头
+var sampleImage = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+// fixtureContent 接口测试的默认 fixture：query 参数取参数名大写，请求体与响应取 schema 示例，二进制响应为图片
+func fixtureContent(api Api) (content []byte, err error) {
+	var f fixture
+	if len(api.Query) > 0 {
+		f.Query = url.Values{}
+		for _, param := range api.Query {
+			f.Query.Set(param.Name, strings.ToUpper(param.Name))
+		}
+	}
+	if hasPayload(api) {
+		f.Body = json.RawMessage(`{}`)
+		if api.Request != nil && len(api.Request.Sample) > 0 {
+			f.Body = api.Request.Sample
+		}
+	}
+	f.Response = json.RawMessage(`{"errcode":0,"errmsg":"ok"}`)
+	if api.Response != nil && len(api.Response.Sample) > 0 {
+		f.Response = api.Response.Sample
+	}
+	if api.Binary {
+		f.ContentType = "image/jpeg"
+		f.Response, err = json.Marshal(sampleImage)
+		if err != nil {
+			return
+		}
+	}
+
+	content, err = json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return
+	}
+	return append(content, '\n'), nil
+}
+
+// hasPayload 接口方法是否有 payload 参数
+func hasPayload(api Api) bool {
+	if api.Upload != nil {
+		return api.Upload.PayloadField != ""
+	}
+	return api.Method == "POST"
+}
+
+// fixtureArgValue 测试中由 fixture 得到的参数取值
+func fixtureArgValue(api Api, arg funcArg, mockCtx string) string {
+	switch {
+	case arg.Name == "ctx":
+		return mockCtx
+	case arg.Name == "appid":
+		return `"AUTHORIZER_APPID"`
+	case arg.Name == "payload":
+		return "[]byte(fixture.Body)"
+	case arg.Name == "params":
+		return "fixture.Query"
+	case isFileField(api, arg.Name):
+		// 上传 fixture 文件本身
+		return `"testdata/` + api.FuncName + `.json"`
+	case api.Upload != nil && arg.Type == "string":
+		// 其他表单字段取字段名大写
+		return `"` + strings.ToUpper(arg.Name) + `"`
+	}
+	return `""`
+}
+
+// requestAssertions 校验请求令牌、query 参数与请求体的测试语句
+func requestAssertions(group ApiGroup, api Api) string {
+	var asserts []string
+
+	switch {
+	case group.Ctx != CtxPlatform:
+		asserts = append(asserts, tokenAssert("access_token", "AUTHORIZER_ACCESS_TOKEN"))
+	case api.Auth == AuthComponent && api.TokenParam != "":
+		// 令牌只放在 token_param 参数中
+		asserts = append(asserts, tokenAssert(api.TokenParam, "ACCESS_TOKEN"), noTokenAssertTpl)
+	case api.Auth == AuthComponent:
+		asserts = append(asserts, tokenAssert("component_access_token", "ACCESS_TOKEN"))
+	case api.Auth == AuthAuthorizer:
+		asserts = append(asserts, tokenAssert("access_token", "AUTHORIZER_ACCESS_TOKEN"))
+	default:
+		asserts = append(asserts, noTokenAssertTpl)
+	}
+
+	if len(api.Query) > 0 {
+		asserts = append(asserts, queryAssertTpl)
+	}
+
+	switch {
+	case api.Upload != nil:
+		for _, field := range api.Upload.FileFields {
+			asserts = append(asserts, strings.ReplaceAll(uploadAssertTpl, "_UPLOAD_", field))
+		}
+		if api.Upload.PayloadField != "" {
+			assert := strings.ReplaceAll(formValueAssertTpl, "_FIELD_NAME_", api.Upload.PayloadField)
+			asserts = append(asserts, strings.ReplaceAll(assert, "_FIELD_VALUE_", "string(fixture.Body)"))
+		}
+		for _, field := range api.Upload.FormFields {
+			assert := strings.ReplaceAll(formValueAssertTpl, "_FIELD_NAME_", field)
+			asserts = append(asserts, strings.ReplaceAll(assert, "_FIELD_VALUE_", `"`+strings.ToUpper(field)+`"`))
+		}
+	case api.Method == "POST":
+		asserts = append(asserts, bodyAssertTpl)
+	}
+
+	return strings.Join(asserts, "")
+}
+
+func tokenAssert(param string, token string) string {
+	assert := strings.ReplaceAll(tokenAssertTpl, "_TOKEN_PARAM_", param)
+	return strings.ReplaceAll(assert, "_TOKEN_", token)
+}
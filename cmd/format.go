@@ -0,0 +1,147 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+)
+
+const licenseHeader = `// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+`
+
+// generatedMarker 生成文件的标记，check 据此找出已不再生成的文件
+const generatedMarker = "// Code generated by cmd from apis.json. DO NOT EDIT."
+
+const modulePath = "github.com/fastwego/wxopen"
+
+// 生成代码中可能用到的包
+var knownImports = map[string]string{
+	"bytes":       "bytes",
+	"errors":      "errors",
+	"fmt":         "fmt",
+	"http":        "net/http",
+	"io":          "io",
+	"json":        "encoding/json",
+	"multipart":   "mime/multipart",
+	"os":          "os",
+	"path":        "path",
+	"reflect":     "reflect",
+	"runtime":     "runtime",
+	"strings":     "strings",
+	"testing":     "testing",
+	"url":         "net/url",
+	"miniprogram": "github.com/fastwego/miniprogram",
+	"offiaccount": "github.com/fastwego/offiaccount",
+	"wxopen":      modulePath,
+	"test":        modulePath + "/test",
+}
+
+/*
+formatSource 为生成的代码补上 license 头、生成标记与 import，并 gofmt
+
+import 由代码中未定义的包名 (如 json.Marshal 中的 json) 推断
+*/
+func formatSource(filename string, pkg string, src []byte) (formatted []byte, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %v", filename, err)
+	}
+
+	imports := map[string]string{}
+	for name, importPath := range knownImports {
+		imports[name] = importPath
+	}
+	if strings.HasSuffix(file.Name.Name, "_test") {
+		// 示例在外部测试包中引用被测包
+		imports[path.Base(pkg)] = modulePath + "/apis/" + pkg
+	}
+
+	var std, ext []string
+	used := map[string]bool{}
+	var unknown error
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok || ident.Obj != nil || used[ident.Name] {
+			return true
+		}
+		used[ident.Name] = true
+
+		importPath, ok := imports[ident.Name]
+		if !ok {
+			unknown = fmt.Errorf("%s: unknown package %s", fset.Position(ident.Pos()), ident.Name)
+			return false
+		}
+		if strings.Contains(importPath, ".") {
+			ext = append(ext, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+		return true
+	})
+	if unknown != nil {
+		return nil, unknown
+	}
+	sort.Strings(std)
+	sort.Strings(ext)
+
+	var block []string
+	for _, importPath := range std {
+		block = append(block, "\t"+`"`+importPath+`"`)
+	}
+	if len(std) > 0 && len(ext) > 0 {
+		block = append(block, "")
+	}
+	for _, importPath := range ext {
+		block = append(block, "\t"+`"`+importPath+`"`)
+	}
+
+	// import 紧跟 package 语句
+	source := string(src)
+	end := fset.Position(file.Name.End()).Offset
+	if len(block) > 0 {
+		source = source[:end] + "\n\nimport (\n" + strings.Join(block, "\n") + "\n)" + source[end:]
+	}
+
+	formatted, err = format.Source([]byte(licenseHeader + generatedMarker + "\n\n" + source))
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %v", filename, err)
+	}
+	return
+}
@@ -0,0 +1,343 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// 接口鉴权方式
+const (
+	AuthComponent  = "component"  // component_access_token
+	AuthAuthorizer = "authorizer" // 授权方 authorizer_access_token
+	AuthUser       = "user"       // 用户 access_token，由调用方放在 query 参数中
+	AuthNone       = "none"       // 无需 access_token
+)
+
+// 接口调用方
+const (
+	CtxPlatform    = ""            // 第三方平台 *wxopen.Platform
+	CtxOffiAccount = "offiaccount" // 代公众号 *offiaccount.OffiAccount
+	CtxMiniprogram = "miniprogram" // 代小程序 *miniprogram.Miniprogram
+)
+
+// Spec 接口描述文件
+type Spec struct {
+	Groups []ApiGroup `json:"groups"`
+}
+
+// ApiGroup 一组接口，对应 apis 下的一个包
+type ApiGroup struct {
+	Name        string `json:"name"`
+	Package     string `json:"package"`
+	Ctx         string `json:"ctx"`         // 接口调用方：默认第三方平台；offiaccount 代公众号调用；miniprogram 代小程序调用
+	Handwritten bool   `json:"handwritten"` // 包代码为手写，仅用于接口列表，不生成代码
+	Apis        []Api  `json:"apis"`
+}
+
+// Api 接口描述
+type Api struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	See         string `json:"see"` // 官方文档链接
+	FuncName    string `json:"func_name"`
+
+	Method     string  `json:"method"` // GET / POST
+	Url        string  `json:"url"`    // 官方文档中的请求示例地址
+	Path       string  `json:"path"`
+	Auth       string  `json:"auth"`
+	TokenParam string  `json:"token_param"` // 非默认的 access_token 参数名，例如以 access_token 传递 component_access_token
+	Redirect   bool    `json:"redirect"`    // 引导用户跳转的链接，只生成拼接地址的方法
+	Query      []Param `json:"query"`
+	Upload     *Upload `json:"upload"`
+	Binary     bool    `json:"binary"` // 响应为图片等二进制内容，方法额外返回 Content-Type
+
+	Request  *Schema `json:"request"`
+	Response *Schema `json:"response"`
+}
+
+// Param query 参数
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// Upload multipart 上传配置
+type Upload struct {
+	FileFields   []string `json:"file_fields"`   // 文件表单字段，对应方法的文件路径参数
+	PayloadField string   `json:"payload_field"` // 以表单字段提交的 json 参数，可选
+	FormFields   []string `json:"form_fields"`   // 其他表单字段，对应方法的 string 参数，可选
+}
+
+// Schema 请求 / 响应 json 结构
+type Schema struct {
+	Fields []Field         `json:"fields"`
+	Sample json.RawMessage `json:"sample"` // 示例 json，用于生成 round-trip 测试
+}
+
+// Field json 字段
+type Field struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"` // string / int / int64 / float64 / bool / object / array
+	Doc      string  `json:"doc"`
+	Required bool    `json:"required"`
+	Fields   []Field `json:"fields"` // object 的字段
+	Items    *Field  `json:"items"`  // array 的元素
+}
+
+// 生成的方法中已使用的参数名
+var reservedArgs = map[string]bool{
+	"ctx": true, "appid": true, "payload": true, "params": true, "resp": true, "err": true,
+	"r": true, "w": true, "m": true, "part": true,
+	// 生成的方法中引用的包名
+	"io": true, "os": true, "path": true, "multipart": true, "url": true, "bytes": true, "json": true,
+}
+
+var fieldTypes = map[string]bool{
+	"string": true, "int": true, "int64": true, "float64": true, "bool": true, "object": true, "array": true,
+}
+
+// loadSpec 读取并校验接口描述文件
+func loadSpec(filename string) (spec Spec, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&spec)
+	if err != nil {
+		return spec, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	err = spec.validate()
+	if err != nil {
+		return spec, fmt.Errorf("%s: %v", filename, err)
+	}
+	return
+}
+
+func (spec Spec) validate() error {
+	packages := map[string]bool{}
+	for _, group := range spec.Groups {
+		if group.Name == "" || group.Package == "" {
+			return fmt.Errorf("group %q: name and package are required", group.Package)
+		}
+		if packages[group.Package] {
+			return fmt.Errorf("group %q: duplicate package", group.Package)
+		}
+		packages[group.Package] = true
+
+		switch group.Ctx {
+		case CtxPlatform, CtxOffiAccount, CtxMiniprogram:
+		default:
+			return fmt.Errorf("group %q: unknown ctx %q", group.Package, group.Ctx)
+		}
+
+		funcNames := map[string]bool{}
+		for _, api := range group.Apis {
+			if funcNames[api.FuncName] {
+				return fmt.Errorf("group %q: duplicate func_name %q", group.Package, api.FuncName)
+			}
+			funcNames[api.FuncName] = true
+
+			if err := api.validate(group); err != nil {
+				return fmt.Errorf("group %q api %q: %v", group.Package, api.FuncName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (api Api) validate(group ApiGroup) error {
+	if api.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !token.IsIdentifier(api.FuncName) || !token.IsExported(api.FuncName) {
+		return fmt.Errorf("func_name must be an exported Go identifier")
+	}
+	if !strings.HasPrefix(api.See, "https://") {
+		return fmt.Errorf("see must be a https doc link")
+	}
+
+	if api.Method != "GET" && api.Method != "POST" {
+		return fmt.Errorf("unknown method %q", api.Method)
+	}
+	parsed, err := url.Parse(api.Url)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid url %q", api.Url)
+	}
+	if !strings.HasPrefix(api.Path, "/") || parsed.Path != api.Path {
+		return fmt.Errorf("path %q does not match url %q", api.Path, api.Url)
+	}
+
+	switch api.Auth {
+	case AuthComponent, AuthAuthorizer, AuthUser, AuthNone:
+	default:
+		return fmt.Errorf("unknown auth %q", api.Auth)
+	}
+	if group.Ctx != CtxPlatform && api.Auth != AuthAuthorizer && !api.Redirect {
+		return fmt.Errorf("ctx %s only supports auth %s", group.Ctx, AuthAuthorizer)
+	}
+	if api.TokenParam != "" && api.Auth != AuthComponent {
+		return fmt.Errorf("token_param is only supported with auth %s", AuthComponent)
+	}
+
+	if api.Redirect {
+		if api.Method != "GET" || api.Auth != AuthNone || api.Request != nil || api.Response != nil {
+			return fmt.Errorf("redirect must be GET with auth %s and no request/response schema", AuthNone)
+		}
+	}
+
+	params := map[string]bool{}
+	for _, param := range api.Query {
+		if param.Name == "" || param.Type != "string" {
+			return fmt.Errorf("query param %q: name is required and type must be string", param.Name)
+		}
+		if params[param.Name] {
+			return fmt.Errorf("duplicate query param %q", param.Name)
+		}
+		params[param.Name] = true
+	}
+	if tokenParam := api.tokenParam(); tokenParam != "" && params[tokenParam] {
+		return fmt.Errorf("query param %q is added by auth %s", tokenParam, api.Auth)
+	}
+
+	if api.Upload != nil {
+		if api.Method != "POST" || len(api.Upload.FileFields) == 0 {
+			return fmt.Errorf("upload must be POST with file_fields")
+		}
+		fields := map[string]bool{}
+		for _, field := range append(append([]string{}, api.Upload.FileFields...), api.Upload.FormFields...) {
+			// 表单字段同时作为方法参数名
+			if !token.IsIdentifier(field) || fields[field] || reservedArgs[field] {
+				return fmt.Errorf("form field %q must be a unique Go identifier", field)
+			}
+			fields[field] = true
+		}
+		for _, field := range api.Upload.FileFields {
+			// io.Reader 版本的文件名参数与打开的文件
+			for _, suffix := range []string{"Filename", "File"} {
+				if fields[field+suffix] {
+					return fmt.Errorf("form field %q conflicts with file field %q", field+suffix, field)
+				}
+			}
+		}
+		if api.Request != nil && api.Upload.PayloadField == "" {
+			return fmt.Errorf("upload with request schema requires payload_field")
+		}
+	}
+	if api.Binary {
+		if group.Ctx != CtxPlatform || api.Redirect || api.Upload != nil || api.Response != nil {
+			return fmt.Errorf("binary is only supported by ctx platform, without upload and response schema")
+		}
+	}
+	if api.Method == "GET" && api.Request != nil {
+		return fmt.Errorf("GET does not take a request body")
+	}
+
+	for _, schema := range []*Schema{api.Request, api.Response} {
+		if schema == nil {
+			continue
+		}
+		if err := validateFields(schema.Fields); err != nil {
+			return err
+		}
+		if len(schema.Sample) > 0 {
+			var sample interface{}
+			if err := json.Unmarshal(schema.Sample, &sample); err != nil {
+				return fmt.Errorf("invalid sample: %v", err)
+			}
+			if err := validateSample(sample, Field{Type: "object", Fields: schema.Fields}); err != nil {
+				return fmt.Errorf("sample: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateFields(fields []Field) error {
+	names := map[string]bool{}
+	for _, field := range fields {
+		if field.Name == "" {
+			return fmt.Errorf("field name is required")
+		}
+		if names[field.Name] {
+			return fmt.Errorf("duplicate field %q", field.Name)
+		}
+		names[field.Name] = true
+
+		if err := validateField(field); err != nil {
+			return fmt.Errorf("field %q: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateField(field Field) error {
+	if !fieldTypes[field.Type] {
+		return fmt.Errorf("unknown type %q", field.Type)
+	}
+	switch field.Type {
+	case "object":
+		return validateFields(field.Fields)
+	case "array":
+		if field.Items == nil {
+			return fmt.Errorf("array requires items")
+		}
+		return validateField(*field.Items)
+	}
+	if len(field.Fields) > 0 || field.Items != nil {
+		return fmt.Errorf("only object/array may have fields/items")
+	}
+	return nil
+}
+
+// tokenParam 鉴权方式自动附加的 access_token 参数名
+func (api Api) tokenParam() string {
+	switch {
+	case api.TokenParam != "":
+		return api.TokenParam
+	case api.Auth == AuthComponent:
+		return "component_access_token"
+	case api.Auth == AuthAuthorizer:
+		return "access_token"
+	}
+	return ""
+}
+
+// requestLine 文档注释中的请求说明，例如 POST https://api.weixin.qq.com/...
+func (api Api) requestLine() string {
+	method := api.Method
+	if api.Upload != nil {
+		method = "POST(@" + strings.Join(api.Upload.FileFields, "|@")
+		if api.Upload.PayloadField != "" {
+			method += "|field=" + api.Upload.PayloadField
+		}
+		for _, field := range api.Upload.FormFields {
+			method += "|field=" + field
+		}
+		method += ")"
+	}
+	return method + " " + api.Url
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validGroup 一组能通过校验的接口描述，测试用例在此基础上修改
+func validGroup() ApiGroup {
+	return ApiGroup{
+		Name:    "开放平台-授权",
+		Package: "auth",
+		Apis: []Api{{
+			Name:     "获取预授权码",
+			See:      "https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/api/pre_auth_code.html",
+			FuncName: "CreatePreauthCode",
+			Method:   "POST",
+			Url:      "https://api.weixin.qq.com/cgi-bin/component/api_create_preauthcode?component_access_token=COMPONENT_ACCESS_TOKEN",
+			Path:     "/cgi-bin/component/api_create_preauthcode",
+			Auth:     AuthComponent,
+		}},
+	}
+}
+
+func TestSpec_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(group *ApiGroup)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(group *ApiGroup) {},
+		},
+		{
+			name:    "missing package",
+			modify:  func(group *ApiGroup) { group.Package = "" },
+			wantErr: "name and package are required",
+		},
+		{
+			name:    "unknown ctx",
+			modify:  func(group *ApiGroup) { group.Ctx = "website" },
+			wantErr: `unknown ctx "website"`,
+		},
+		{
+			name:    "duplicate func_name",
+			modify:  func(group *ApiGroup) { group.Apis = append(group.Apis, group.Apis[0]) },
+			wantErr: `duplicate func_name "CreatePreauthCode"`,
+		},
+		{
+			name:    "unexported func_name",
+			modify:  func(group *ApiGroup) { group.Apis[0].FuncName = "createPreauthCode" },
+			wantErr: "func_name must be an exported Go identifier",
+		},
+		{
+			name:    "http see",
+			modify:  func(group *ApiGroup) { group.Apis[0].See = "http://developers.weixin.qq.com" },
+			wantErr: "see must be a https doc link",
+		},
+		{
+			name:    "unknown method",
+			modify:  func(group *ApiGroup) { group.Apis[0].Method = "PUT" },
+			wantErr: `unknown method "PUT"`,
+		},
+		{
+			name:    "path mismatch",
+			modify:  func(group *ApiGroup) { group.Apis[0].Path = "/cgi-bin/component/api_component_token" },
+			wantErr: "does not match url",
+		},
+		{
+			name:    "unknown auth",
+			modify:  func(group *ApiGroup) { group.Apis[0].Auth = "secret" },
+			wantErr: `unknown auth "secret"`,
+		},
+		{
+			name:    "ctx auth",
+			modify:  func(group *ApiGroup) { group.Ctx = CtxMiniprogram },
+			wantErr: "ctx miniprogram only supports auth authorizer",
+		},
+		{
+			name: "token_param auth",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Auth = AuthAuthorizer
+				group.Apis[0].TokenParam = "access_token"
+			},
+			wantErr: "token_param is only supported with auth component",
+		},
+		{
+			name:    "query token param",
+			modify:  func(group *ApiGroup) { group.Apis[0].Query = []Param{{Name: "component_access_token", Type: "string"}} },
+			wantErr: `query param "component_access_token" is added by auth component`,
+		},
+		{
+			name:    "redirect POST",
+			modify:  func(group *ApiGroup) { group.Apis[0].Redirect = true },
+			wantErr: "redirect must be GET",
+		},
+		{
+			name:    "upload without file fields",
+			modify:  func(group *ApiGroup) { group.Apis[0].Upload = &Upload{} },
+			wantErr: "upload must be POST with file_fields",
+		},
+		{
+			name: "upload reserved field",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Upload = &Upload{FileFields: []string{"media"}, FormFields: []string{"ctx"}}
+			},
+			wantErr: `form field "ctx" must be a unique Go identifier`,
+		},
+		{
+			name: "upload filename conflict",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Upload = &Upload{FileFields: []string{"media"}, FormFields: []string{"mediaFilename"}}
+			},
+			wantErr: `form field "mediaFilename" conflicts with file field "media"`,
+		},
+		{
+			name: "GET request body",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Method = "GET"
+				group.Apis[0].Request = &Schema{}
+			},
+			wantErr: "GET does not take a request body",
+		},
+		{
+			name: "unknown field type",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Response = &Schema{Fields: []Field{{Name: "count", Type: "uint"}}}
+			},
+			wantErr: `field "count": unknown type "uint"`,
+		},
+		{
+			name: "array without items",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Response = &Schema{Fields: []Field{{Name: "list", Type: "array"}}}
+			},
+			wantErr: `field "list": array requires items`,
+		},
+		{
+			name: "sample type mismatch",
+			modify: func(group *ApiGroup) {
+				group.Apis[0].Response = &Schema{
+					Fields: []Field{{Name: "expires_in", Type: "int"}},
+					Sample: json.RawMessage(`{"expires_in": "600"}`),
+				}
+			},
+			wantErr: "sample:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := validGroup()
+			tt.modify(&group)
+
+			err := Spec{Groups: []ApiGroup{group}}.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: `{"groups": [{"name": "开放平台-授权", "package": "auth", "apis": []}]}`},
+		{name: "unknown field", content: `{"groups": [{"name": "开放平台-授权", "package": "auth", "api": []}]}`, wantErr: `unknown field "api"`},
+		{name: "invalid spec", content: `{"groups": [{"name": "开放平台-授权", "package": ""}]}`, wantErr: "name and package are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".json")
+			if err := ioutil.WriteFile(filename, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := loadSpec(filename)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("loadSpec() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), filename) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadSpec() error = %v, want %s: %q", err, filename, tt.wantErr)
+			}
+		})
+	}
+}
+
+// 仓库中的 apis.json 必须通过校验
+func TestLoadSpec_apis(t *testing.T) {
+	if _, err := loadSpec("apis.json"); err != nil {
+		t.Errorf("loadSpec(apis.json) error = %v", err)
+	}
+}
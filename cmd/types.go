@@ -0,0 +1,182 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goTypes 字段类型对应的 Go 类型
+var goTypes = map[string]string{
+	"string":  "string",
+	"int":     "int",
+	"int64":   "int64",
+	"float64": "float64",
+	"bool":    "bool",
+}
+
+// toCamel 将 snake_case 转换为 CamelCase，例如 authorizer_appid => AuthorizerAppid
+func toCamel(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+/*
+structBuilder 生成 schema 对应的结构体定义
+
+嵌套 object 生成独立的结构体，命名为方法名 + 字段名，与包内已有类型重名时再加上父结构体名
+*/
+type structBuilder struct {
+	names map[string]bool
+	decls []string
+}
+
+func newStructBuilder() *structBuilder {
+	return &structBuilder{names: map[string]bool{}}
+}
+
+// build 生成名为 typeName 的结构体，prefix 为嵌套结构体的命名前缀
+func (builder *structBuilder) build(typeName string, prefix string, doc string, fields []Field, omitempty bool) {
+	builder.names[typeName] = true
+
+	// 先占位，保证父结构体排在嵌套结构体之前
+	index := len(builder.decls)
+	builder.decls = append(builder.decls, "")
+
+	var lines []string
+	for _, field := range fields {
+		goType := builder.fieldGoType(typeName, prefix, field, omitempty)
+
+		tag := field.Name
+		if omitempty && !field.Required {
+			tag += ",omitempty"
+		}
+		line := "\t" + toCamel(field.Name) + " " + goType + " `json:" + strconv.Quote(tag) + "`"
+		if field.Doc != "" {
+			line += " // " + field.Doc
+		}
+		lines = append(lines, line)
+	}
+
+	decl := "\n// " + typeName + " " + doc + "\ntype " + typeName + " struct {\n" + strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		decl += "\n"
+	}
+	decl += "}\n"
+
+	builder.decls[index] = decl
+}
+
+// fieldGoType 计算字段的 Go 类型，object 字段生成嵌套结构体
+func (builder *structBuilder) fieldGoType(parent string, prefix string, field Field, omitempty bool) string {
+	switch field.Type {
+	case "object":
+		name := prefix + toCamel(field.Name)
+		if builder.names[name] {
+			name = parent + toCamel(field.Name)
+		}
+		builder.build(name, prefix, field.Doc, field.Fields, omitempty)
+		return name
+	case "array":
+		items := *field.Items
+		if items.Name == "" {
+			items.Name = field.Name
+		}
+		if items.Doc == "" {
+			items.Doc = field.Doc
+		}
+		return "[]" + builder.fieldGoType(parent, prefix, items, omitempty)
+	}
+	return goTypes[field.Type]
+}
+
+// String 输出全部结构体定义
+func (builder *structBuilder) String() string {
+	return strings.Join(builder.decls, "")
+}
+
+// sampleLiteral 以 Go 字符串字面量输出示例 json
+func sampleLiteral(sample []byte) string {
+	s := string(sample)
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// validateSample 校验示例 json 只包含 schema 中声明的字段，且类型一致
+func validateSample(value interface{}, field Field) error {
+	switch field.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("want object")
+		}
+		declared := map[string]Field{}
+		for _, f := range field.Fields {
+			declared[f.Name] = f
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f, ok := declared[key]
+			if !ok {
+				return fmt.Errorf("undeclared field %q", key)
+			}
+			if err := validateSample(object[key], f); err != nil {
+				return fmt.Errorf("field %q: %v", key, err)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("want array")
+		}
+		for i, item := range array {
+			if err := validateSample(item, *field.Items); err != nil {
+				return fmt.Errorf("item %d: %v", i, err)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("want string")
+		}
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("want bool")
+		}
+	case "int", "int64":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			return fmt.Errorf("want integer")
+		}
+	case "float64":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("want number")
+		}
+	}
+	return nil
+}
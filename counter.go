@@ -0,0 +1,79 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"sync"
+)
+
+/*
+ApiCallCounter 内存接口调用计数器
+
+按 appid + 接口路径统计调用次数，用于查看哪个授权方在消耗 quota
+
+	counter := wxopen.NewApiCallCounter()
+	platform.RecordApiCallHandler = counter.Record
+*/
+type ApiCallCounter struct {
+	mutex  sync.Mutex
+	counts map[string]map[string]int64
+}
+
+// NewApiCallCounter 创建接口调用计数器
+func NewApiCallCounter() *ApiCallCounter {
+	return &ApiCallCounter{counts: map[string]map[string]int64{}}
+}
+
+// Record 记录一次接口调用，签名满足 RecordApiCallFunc
+func (counter *ApiCallCounter) Record(platform *Platform, appid string, path string) {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	if counter.counts[appid] == nil {
+		counter.counts[appid] = map[string]int64{}
+	}
+	counter.counts[appid][path]++
+}
+
+// Count 返回 appid 调用接口 path 的次数
+func (counter *ApiCallCounter) Count(appid string, path string) int64 {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	return counter.counts[appid][path]
+}
+
+// Counts 返回全部计数快照 appid => path => 次数
+func (counter *ApiCallCounter) Counts() map[string]map[string]int64 {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	snapshot := make(map[string]map[string]int64, len(counter.counts))
+	for appid, paths := range counter.counts {
+		snapshot[appid] = make(map[string]int64, len(paths))
+		for path, count := range paths {
+			snapshot[appid][path] = count
+		}
+	}
+	return snapshot
+}
+
+// Reset 清空计数
+func (counter *ApiCallCounter) Reset() {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.counts = map[string]map[string]int64{}
+}
@@ -6,5 +6,4 @@ require (
 	github.com/faabiosr/cachego v0.16.1
 	github.com/fastwego/miniprogram v1.0.0-beta.3
 	github.com/fastwego/offiaccount v1.0.0-beta.11
-	github.com/iancoleman/strcase v0.1.2
 )
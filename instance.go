@@ -0,0 +1,124 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/fastwego/miniprogram"
+	"github.com/fastwego/offiaccount"
+)
+
+/*
+instanceTransport 在授权方公众号/小程序实例的请求发出前限流并记录接口调用
+
+fastwego/offiaccount、fastwego/miniprogram 固定使用 http.DefaultClient 发送请求，无法为实例单独设置 http.Client，
+因此首次创建实例时包装 http.DefaultClient.Transport：按 User-Agent 识别实例请求，按请求中的 access_token 找到所属平台与授权方 appid，
+令牌不足时返回 *RateLimitError（包装在 *url.Error 中），请求不会发出；重试同样经过这里
+
+access_token 过期或系统繁忙时，两个库会把已读完请求体的同一个请求再发送一次，
+因此实例请求每次发出前都用 GetBody 重新生成请求体；无法重新生成的流式请求体仍会在重试时出错
+
+其他请求原样转发给原来的 Transport；之后再替换 http.DefaultClient.Transport 会使实例请求不再限流与记录
+*/
+type instanceTransport struct {
+	next http.RoundTripper
+
+	mutex   sync.RWMutex
+	callers map[string]instanceCaller // access_token => 调用方
+	tokens  map[instanceCaller]string // 调用方 => 最近一次使用的 access_token
+}
+
+// instanceCaller 实例请求的调用方
+type instanceCaller struct {
+	platform *Platform
+	appid    string
+}
+
+var (
+	instanceHook        = &instanceTransport{callers: map[string]instanceCaller{}, tokens: map[instanceCaller]string{}}
+	installInstanceHook sync.Once
+)
+
+// hookInstances 包装 http.DefaultClient.Transport（只执行一次）
+func hookInstances() {
+	installInstanceHook.Do(func() {
+		instanceHook.next = http.DefaultClient.Transport
+		if instanceHook.next == nil {
+			instanceHook.next = http.DefaultTransport
+		}
+		http.DefaultClient.Transport = instanceHook
+	})
+}
+
+// register 记录实例获取到的 access_token，旧的 access_token 随之失效
+func (t *instanceTransport) register(platform *Platform, appid string, accessToken string) {
+	caller := instanceCaller{platform: platform, appid: appid}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if old, ok := t.tokens[caller]; ok && old != accessToken {
+		delete(t.callers, old)
+	}
+	t.tokens[caller] = accessToken
+	t.callers[accessToken] = caller
+}
+
+func (t *instanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Header.Get("User-Agent") {
+	case offiaccount.UserAgent, miniprogram.UserAgent:
+		t.mutex.RLock()
+		caller, ok := t.callers[req.URL.Query().Get("access_token")]
+		t.mutex.RUnlock()
+
+		if ok {
+			if err := caller.platform.limitAndRecord(caller.appid, req.URL.Path); err != nil {
+				// RoundTripper 出错时也需要关闭请求体
+				if req.Body != nil {
+					_ = req.Body.Close()
+				}
+				return nil, err
+			}
+		}
+
+		if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			_ = req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// limitAndRecord appid 调用 path 前限流并记录接口调用
+func (platform *Platform) limitAndRecord(appid string, path string) (err error) {
+	if platform.RateLimiter != nil {
+		err = platform.RateLimiter.Take(appid, path)
+		if err != nil {
+			return
+		}
+	}
+
+	if platform.RecordApiCallHandler != nil {
+		platform.RecordApiCallHandler(platform, appid, path)
+	}
+	return
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestInstanceTransport_RewindBody(t *testing.T) {
+	bodies := []string{}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return "REWIND_ACCESS_TOKEN", nil
+	}
+	offiAccount, _ := platform.NewOffiAccount("AUTHORIZER_APPID")
+	if _, err := offiAccount.AccessToken.GetAccessTokenHandler(offiAccount); err != nil {
+		t.Fatalf("GetAccessTokenHandler() error = %v", err)
+	}
+
+	// 与 fastwego/offiaccount 重试时一样，把同一个请求发送两次
+	newBody := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(io.MultiReader(strings.NewReader("payload"))), nil
+	}
+	body, _ := newBody()
+	req, err := http.NewRequest(http.MethodPost, svr.URL+"/api?access_token=REWIND_ACCESS_TOKEN", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.ContentLength = int64(len("payload"))
+	req.GetBody = newBody
+	req.Header.Set("User-Agent", offiaccount.UserAgent)
+
+	for i := 0; i < 2; i++ {
+		response, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		_ = response.Body.Close()
+	}
+	if want := []string{"payload", "payload"}; !reflect.DeepEqual(bodies, want) {
+		t.Errorf("bodies = %v, want %v", bodies, want)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastwego/offiaccount/util"
+)
+
+const (
+	TicketTypeJSApi  = "jsapi"   // jsapi_ticket
+	TicketTypeWxCard = "wx_card" // 卡券 api_ticket
+
+	apiGetTicket = "/cgi-bin/ticket/getticket"
+)
+
+/*
+JSSDKConfig 代公众号使用 JS-SDK 时 wx.config 所需的签名配置
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Official_Accounts/js_sdk_instructions.html
+*/
+type JSSDKConfig struct {
+	AppId     string `json:"appId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	Signature string `json:"signature"`
+}
+
+// 防止多个 goroutine 并发刷新冲突
+var refreshAuthorizerTicketLock sync.Mutex
+
+/*
+GetAuthorizerTicket 获取授权方公众号的 jsapi_ticket / 卡券 api_ticket
+
+框架默认将 ticket 缓存在 Platform.Cache 中，过期后使用 authorizer_access_token 重新获取
+
+See: https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/JS-SDK.html#62
+
+GET https://api.weixin.qq.com/cgi-bin/ticket/getticket?access_token=ACCESS_TOKEN&type=jsapi
+*/
+func (platform *Platform) GetAuthorizerTicket(appid string, ticketType string) (ticket string, err error) {
+	cacheKey := ticketType + "_ticket:" + appid
+	ticket, err = platform.Cache.Fetch(cacheKey)
+	if ticket != "" {
+		return
+	}
+
+	refreshAuthorizerTicketLock.Lock()
+	defer refreshAuthorizerTicketLock.Unlock()
+
+	ticket, err = platform.Cache.Fetch(cacheKey)
+	if ticket != "" {
+		return
+	}
+
+	resp, err := platform.Client.HTTPGetWithAuth(AuthAuthorizer(appid), apiGetTicket+"?type="+ticketType)
+	if err != nil {
+		return
+	}
+
+	result := struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}{}
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+	if result.Ticket == "" {
+		err = fmt.Errorf("%s", string(resp))
+		return
+	}
+
+	// 过期时间设置为 0.9 * expiresIn 提供一定冗余
+	d := time.Duration(result.ExpiresIn) * time.Second * 9 / 10
+	_ = platform.Cache.Save(cacheKey, result.Ticket, d)
+
+	return result.Ticket, nil
+}
+
+/*
+GetJSSDKConfig 生成授权方公众号页面 wx.config 签名配置
+
+url 为调用 JS-SDK 的当前网页完整 URL，# 及其后面部分不参与签名
+*/
+func (platform *Platform) GetJSSDKConfig(appid string, url string) (config JSSDKConfig, err error) {
+	ticket, err := platform.GetAuthorizerTicket(appid, TicketTypeJSApi)
+	if err != nil {
+		return
+	}
+
+	config = JSSDKConfig{
+		AppId:     appid,
+		Timestamp: time.Now().Unix(),
+		NonceStr:  util.GetRandString(16),
+	}
+	config.Signature = JSSDKSignature(ticket, config.NonceStr, config.Timestamp, url)
+	return
+}
+
+/*
+JSSDKSignature 计算 JS-SDK 签名
+
+对 jsapi_ticket、noncestr、timestamp、url 按字段名 ASCII 码排序后拼接，再 sha1
+*/
+func JSSDKSignature(ticket string, nonceStr string, timestamp int64, url string) (signature string) {
+	if i := strings.Index(url, "#"); i >= 0 {
+		url = url[:i]
+	}
+
+	h := sha1.New()
+	_, _ = io.WriteString(h, "jsapi_ticket="+ticket+"&noncestr="+nonceStr+"&timestamp="+strconv.FormatInt(timestamp, 10)+"&url="+url)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faabiosr/cachego/sync"
+)
+
+func TestJSSDKSignature(t *testing.T) {
+	// 官方文档示例
+	got := JSSDKSignature("sM4AOVdWfPE4DxkXGEs8VMCPGGVi4C3VM0P37wVUCFvkVAy_90u5h9nbSlYy3-Sl-HhTdfl2fzFy1AOcHKP7qg", "Wm3WZYTPz0wzccnW", 1414587457, "http://mp.weixin.qq.com?params=value#hash")
+	want := "0f9de62fce790f9a083d5c99e95740ceb90c27ed"
+	if got != want {
+		t.Errorf("JSSDKSignature() = %v, want %v", got, want)
+	}
+}
+
+func TestPlatform_GetJSSDKConfig(t *testing.T) {
+	requests := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != apiGetTicket || r.URL.Query().Get("access_token") != "AUTHORIZER_ACCESS_TOKEN" || r.URL.Query().Get("type") != TicketTypeJSApi {
+			_, _ = w.Write([]byte(`{"errcode":40097,"errmsg":"invalid args"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","ticket":"TICKET","expires_in":7200}`))
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	platform.Cache = sync.New()
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return "AUTHORIZER_ACCESS_TOKEN", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		config, err := platform.GetJSSDKConfig("AUTHORIZER_APPID", "https://example.com/page?a=1#top")
+		if err != nil {
+			t.Fatalf("GetJSSDKConfig() error = %v", err)
+		}
+		want := JSSDKSignature("TICKET", config.NonceStr, config.Timestamp, "https://example.com/page?a=1")
+		if config.AppId != "AUTHORIZER_APPID" || config.Signature != want {
+			t.Errorf("GetJSSDKConfig() = %+v, want signature %v", config, want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("GetJSSDKConfig() getticket requests = %d, want 1", requests)
+	}
+}
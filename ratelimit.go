@@ -0,0 +1,149 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+RateLimit 令牌桶配置
+
+Rate 为每秒补充的令牌数，Burst 为桶容量（允许的突发请求数）；Rate <= 0 表示不限制
+*/
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+/*
+RateLimitError 请求被本地限流拦截，未发往微信服务器
+*/
+type RateLimitError struct {
+	Appid      string
+	Path       string
+	RetryAfter time.Duration // 预计可再次请求的等待时间
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited: appid %s path %s retry after %s", e.Appid, e.Path, e.RetryAfter)
+}
+
+/*
+RateLimiter 按 appid + 接口路径的令牌桶限流器
+
+	limiter := wxopen.NewRateLimiter(wxopen.RateLimit{Rate: 10, Burst: 20})
+	limiter.SetLimit("/cgi-bin/message/custom/send", wxopen.RateLimit{Rate: 1, Burst: 5})
+	platform.RateLimiter = limiter
+
+Wait 为 true 时阻塞等待令牌，否则立即返回 *RateLimitError
+*/
+type RateLimiter struct {
+	Wait bool
+
+	mutex        sync.Mutex
+	defaultLimit RateLimit
+	limits       map[string]RateLimit
+	buckets      map[string]map[string]*tokenBucket // path => appid => 令牌桶
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter 创建限流器，defaultLimit 作用于未单独配置的接口
+func NewRateLimiter(defaultLimit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		defaultLimit: defaultLimit,
+		limits:       map[string]RateLimit{},
+		buckets:      map[string]map[string]*tokenBucket{},
+	}
+}
+
+// SetLimit 单独配置接口 path 的限流（每个 appid 独立计算）
+func (limiter *RateLimiter) SetLimit(path string, limit RateLimit) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.limits[path] = limit
+	delete(limiter.buckets, path)
+}
+
+/*
+Take 为 appid 调用 path 获取一个令牌
+
+Wait 为 false 且令牌不足时返回 *RateLimitError
+*/
+func (limiter *RateLimiter) Take(appid string, path string) (err error) {
+	wait, err := limiter.reserve(appid, path, time.Now())
+	if err != nil {
+		return
+	}
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return
+}
+
+// reserve 取出令牌，返回需要等待的时间
+func (limiter *RateLimiter) reserve(appid string, path string, now time.Time) (wait time.Duration, err error) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limit, ok := limiter.limits[path]
+	if !ok {
+		limit = limiter.defaultLimit
+	}
+	if limit.Rate <= 0 {
+		return
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	if limiter.buckets[path] == nil {
+		limiter.buckets[path] = map[string]*tokenBucket{}
+	}
+	bucket := limiter.buckets[path][appid]
+	if bucket == nil {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		limiter.buckets[path][appid] = bucket
+	}
+
+	// 补充令牌
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit.Rate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return
+	}
+
+	wait = time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+	if !limiter.Wait {
+		return 0, &RateLimitError{Appid: appid, Path: path, RetryAfter: wait}
+	}
+
+	// 预占令牌，等待期间的补充归当前请求
+	bucket.tokens--
+	return
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fastwego/offiaccount"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 1, Burst: 2})
+	limiter.SetLimit("/unlimited", RateLimit{})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.reserve("APPID", "/api", now); err != nil {
+			t.Fatalf("reserve() #%d error = %v", i, err)
+		}
+	}
+
+	_, err := limiter.reserve("APPID", "/api", now)
+	var rateLimitError *RateLimitError
+	if !errors.As(err, &rateLimitError) || rateLimitError.Appid != "APPID" || rateLimitError.Path != "/api" || rateLimitError.RetryAfter != time.Second {
+		t.Errorf("reserve() error = %v, want *RateLimitError retry after 1s", err)
+	}
+
+	// 其他 appid 独立计数
+	if _, err = limiter.reserve("OTHER_APPID", "/api", now); err != nil {
+		t.Errorf("reserve() other appid error = %v", err)
+	}
+
+	// 不限流接口
+	for i := 0; i < 10; i++ {
+		if _, err = limiter.reserve("APPID", "/unlimited", now); err != nil {
+			t.Errorf("reserve() unlimited error = %v", err)
+		}
+	}
+
+	// 令牌补充后可以继续
+	if _, err = limiter.reserve("APPID", "/api", now.Add(time.Second)); err != nil {
+		t.Errorf("reserve() after refill error = %v", err)
+	}
+
+	// Wait 模式预占令牌并返回等待时间
+	limiter.Wait = true
+	wait, err := limiter.reserve("APPID", "/api", now.Add(time.Second))
+	if err != nil || wait != time.Second {
+		t.Errorf("reserve() wait = %v, %v, want 1s", wait, err)
+	}
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	requests := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer svr.Close()
+	wxServerUrl := WXServerUrl
+	WXServerUrl = svr.URL
+	defer func() { WXServerUrl = wxServerUrl }()
+
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+	platform.GetComponentAccessTokenHandler = func(platform *Platform) (string, error) {
+		return "COMPONENT_ACCESS_TOKEN", nil
+	}
+	platform.GetAuthorizerAccessTokenHandler = func(platform *Platform, appid string) (string, error) {
+		return "AUTHORIZER_ACCESS_TOKEN", nil
+	}
+	platform.RateLimiter = NewRateLimiter(RateLimit{Rate: 0.001, Burst: 1})
+
+	if _, err := platform.Client.HTTPGet("/api"); err != nil {
+		t.Fatalf("HTTPGet() error = %v", err)
+	}
+	if _, err := platform.Client.HTTPGet("/api"); !errors.As(err, new(*RateLimitError)) {
+		t.Errorf("HTTPGet() error = %v, want *RateLimitError", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+
+	// 授权方公众号实例按真实接口路径限流
+	paths := []string{}
+	svr.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/busy" {
+			_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system busy"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	offiaccountServerUrl := offiaccount.WXServerUrl
+	offiaccount.WXServerUrl = svr.URL
+	defer func() { offiaccount.WXServerUrl = offiaccountServerUrl }()
+
+	platform.RateLimiter = NewRateLimiter(RateLimit{})
+	platform.RateLimiter.SetLimit("/busy", RateLimit{Rate: 0.001, Burst: 2})
+	platform.RateLimiter.SetLimit("/limited", RateLimit{Rate: 0.001, Burst: 1})
+
+	// 平台 client 的重试同样计数
+	_, _ = platform.Client.HTTPGet("/busy")
+	if _, err := platform.Client.HTTPGet("/busy"); !errors.As(err, new(*RateLimitError)) {
+		t.Errorf("HTTPGet() after retry error = %v, want *RateLimitError", err)
+	}
+
+	// 限流不依赖实例的 Logger
+	offiAccount, _ := platform.NewOffiAccount("AUTHORIZER_APPID")
+	offiAccount.Logger = nil
+	paths = nil
+
+	_, _ = offiAccount.Client.HTTPGet("/busy")
+	if _, err := offiAccount.Client.HTTPGet("/busy"); !errors.As(err, new(*RateLimitError)) {
+		t.Errorf("offiaccount HTTPGet() after retry error = %v, want *RateLimitError", err)
+	}
+	if _, err := offiAccount.Client.HTTPGet("/limited"); err != nil {
+		t.Errorf("offiaccount HTTPGet() error = %v", err)
+	}
+	_, err := offiAccount.Client.HTTPGet("/limited")
+	var rateLimitError *RateLimitError
+	if !errors.As(err, &rateLimitError) || rateLimitError.Appid != "AUTHORIZER_APPID" || rateLimitError.Path != "/limited" {
+		t.Errorf("offiaccount HTTPGet() error = %v, want *RateLimitError", err)
+	}
+	if want := []string{"/busy", "/busy", "/limited"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("offiaccount requests = %v, want %v", paths, want)
+	}
+}
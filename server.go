@@ -16,55 +16,60 @@ package wxopen
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/offiaccount/type/type_message"
 	"github.com/fastwego/offiaccount/util"
+	"github.com/fastwego/wxopen/type/type_event"
 	"github.com/fastwego/wxopen/type/type_platform"
 )
 
+var (
+	ErrorAuthorizerAppidNotFound = errors.New("authorizer appid not found")
+	ErrorInvalidSignature        = errors.New("invalid signature")
+	ErrorPlaintextMessage        = errors.New("plaintext message rejected: AesKey is configured")
+)
+
+// ReplyEmpty 处理方法返回 ReplyEmpty 时回复空串，而不是默认的 success
+var ReplyEmpty interface{} = emptyReply{}
+
+type emptyReply struct{}
+
+// AuthorizerMessageHandlerFunc 处理授权方消息/事件方法接口，返回的 reply 将作为被动回复消息（为 nil 时回复 success）
+type AuthorizerMessageHandlerFunc func(appid string, message interface{}) (reply interface{}, err error)
+
 /*
 响应微信请求 或 推送消息/事件 的服务器
 */
 type Server struct {
 	Ctx *Platform
+
+	authorizerHandlersLock sync.RWMutex
+	authorizerHandlers     map[string]AuthorizerMessageHandlerFunc
+	allAuthorizersHandler  AuthorizerMessageHandlerFunc
 }
 
 // ParseXML 解析微信推送过来的消息/事件
 func (s *Server) ParseXML(body []byte) (m interface{}, err error) {
 
-	if s.Ctx.Logger != nil {
-		s.Ctx.Logger.Println(string(body))
-	}
-
-	// 是否加密消息
-	encryptMsg := type_message.EncryptMessage{}
-	err = xml.Unmarshal(body, &encryptMsg)
+	body, err = s.decryptXML(body)
 	if err != nil {
 		return
 	}
 
-	// 需要解密
-	if encryptMsg.Encrypt != "" {
-		var xmlMsg []byte
-		_, xmlMsg, _, err = util.AESDecryptMsg(encryptMsg.Encrypt, s.Ctx.Config.AesKey)
-		if err != nil {
-			return
-		}
-		body = xmlMsg
-
-		if s.Ctx.Logger != nil {
-			s.Ctx.Logger.Println("AESDecryptMsg ", string(body))
-		}
-	}
-
 	event := type_platform.Event{}
 	err = xml.Unmarshal(body, &event)
 	//fmt.Println(message)
@@ -102,16 +107,273 @@ func (s *Server) ParseXML(body []byte) (m interface{}, err error) {
 			return
 		}
 		return msg, nil
+	case type_platform.EventTypeNotifyThirdFasteregister:
+		msg := type_platform.EventNotifyThirdFasteregister{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case type_platform.EventTypeNotifyThirdFastRegisterBetaApp:
+		msg := type_platform.EventNotifyThirdFastRegisterBetaApp{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
 
 	}
 	return
 }
 
+/*
+ParseAuthorizerXML 解析微信推送到消息与事件接收 URL 的授权方消息/事件
+
+小程序代码审核结果等开放平台特有的事件解析为 type_event 中的类型，用户消息及其他事件解析为公众号 SDK type_message/type_event 中的类型
+*/
+func (s *Server) ParseAuthorizerXML(body []byte) (m interface{}, err error) {
+	body, err = s.decryptXML(body)
+	if err != nil {
+		return
+	}
+
+	message := type_message.Message{}
+	err = xml.Unmarshal(body, &message)
+	if err != nil {
+		return
+	}
+
+	if message.MsgType == type_message.MsgTypeEvent {
+		m, err = parseAuthorizerEvent(body)
+		if m != nil || err != nil {
+			return
+		}
+	}
+
+	// 用户消息及其他事件复用公众号 SDK 解析
+	return offiAccountServer.ParseXML(body)
+}
+
+// offiAccountServer 用于解析已解密的公众号消息/事件
+var offiAccountServer = offiaccount.Server{Ctx: &offiaccount.OffiAccount{}}
+
+/*
+HandleAuthorizer 注册指定授权方的消息/事件处理方法
+*/
+func (s *Server) HandleAuthorizer(appid string, handler AuthorizerMessageHandlerFunc) {
+	s.authorizerHandlersLock.Lock()
+	defer s.authorizerHandlersLock.Unlock()
+
+	if s.authorizerHandlers == nil {
+		s.authorizerHandlers = map[string]AuthorizerMessageHandlerFunc{}
+	}
+	s.authorizerHandlers[appid] = handler
+}
+
+/*
+HandleAllAuthorizers 注册全局消息/事件处理方法
+
+未通过 HandleAuthorizer 单独注册的授权方，其消息/事件都交由该方法处理
+*/
+func (s *Server) HandleAllAuthorizers(handler AuthorizerMessageHandlerFunc) {
+	s.authorizerHandlersLock.Lock()
+	defer s.authorizerHandlersLock.Unlock()
+
+	s.allAuthorizersHandler = handler
+}
+
+// AllAuthorizersHandler 返回 HandleAllAuthorizers 注册的全局处理方法，未注册时为 nil
+func (s *Server) AllAuthorizersHandler() AuthorizerMessageHandlerFunc {
+	s.authorizerHandlersLock.RLock()
+	defer s.authorizerHandlersLock.RUnlock()
+
+	return s.allAuthorizersHandler
+}
+
+// authorizerHandler 获取授权方对应的处理方法
+func (s *Server) authorizerHandler(appid string) AuthorizerMessageHandlerFunc {
+	s.authorizerHandlersLock.RLock()
+	defer s.authorizerHandlersLock.RUnlock()
+
+	if handler, ok := s.authorizerHandlers[appid]; ok {
+		return handler
+	}
+	return s.allAuthorizersHandler
+}
+
+/*
+ServeAuthorizerMessage 处理推送到消息与事件接收 URL 的授权方消息/事件
+
+从请求地址中获取授权方 appid，校验签名、解密解析消息后分发给注册的处理方法，并将处理方法返回的消息 (加密)回复给微信服务器
+
+签名不正确时返回 ErrorInvalidSignature；配置了 AesKey 时明文消息返回 ErrorPlaintextMessage
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Message_Push/Message_Push_Processing.html
+*/
+func (s *Server) ServeAuthorizerMessage(writer http.ResponseWriter, request *http.Request) (err error) {
+	appid := s.Ctx.GetAuthorizerAppidHandler(s.Ctx, request)
+	if appid == "" {
+		return ErrorAuthorizerAppidNotFound
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return
+	}
+
+	err = s.verifyMessage(request, body)
+	if err != nil {
+		return
+	}
+
+	message, err := s.ParseAuthorizerXML(body)
+	if err != nil {
+		return
+	}
+
+	var reply interface{}
+	if handler := s.authorizerHandler(appid); handler != nil && message != nil {
+		reply, err = handler(appid, message)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.Response(writer, request, reply)
+}
+
+/*
+verifyMessage 使用第三方平台 Token 校验推送消息的签名
+
+加密消息校验 msg_signature；配置了 AesKey 时拒绝明文消息，否则明文消息校验 signature
+
+See: https://developers.weixin.qq.com/doc/oplatform/Third-party_Platforms/Message_Push/Message_encryption_and_decryption.html
+*/
+func (s *Server) verifyMessage(request *http.Request, body []byte) (err error) {
+	encryptMsg := type_message.EncryptMessage{}
+	err = xml.Unmarshal(body, &encryptMsg)
+	if err != nil {
+		return
+	}
+
+	query := request.URL.Query()
+	want, got := "", ""
+	if encryptMsg.Encrypt == "" {
+		if s.Ctx.Config.AesKey != "" {
+			return ErrorPlaintextMessage
+		}
+		want = signature(s.Ctx.Config.Token, query.Get("timestamp"), query.Get("nonce"))
+		got = query.Get("signature")
+	} else {
+		want = signature(s.Ctx.Config.Token, query.Get("timestamp"), query.Get("nonce"), encryptMsg.Encrypt)
+		got = query.Get("msg_signature")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return ErrorInvalidSignature
+	}
+	return
+}
+
+// signature 消息签名：参数字典序排序后拼接 sha1
+func signature(strs ...string) string {
+	sort.Strings(strs)
+	h := sha1.New()
+	_, _ = io.WriteString(h, strings.Join(strs, ""))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// 授权方 appid 格式
+var authorizerAppidPattern = regexp.MustCompile(`^wx[0-9a-f]{16}$`)
+
+/*
+GetAuthorizerAppid 从消息与事件接收 URL 中获取授权方 appid
+
+消息与事件接收 URL 须包含 /$APPID$/ 路径，如 https://example.com/wxopen/$APPID$/callback ，框架默认取路径中符合 appid 格式的部分
+
+如果 URL 格式不同，可以自定义 Platform.GetAuthorizerAppidHandler
+*/
+func GetAuthorizerAppid(platform *Platform, request *http.Request) (appid string) {
+	for _, segment := range strings.Split(request.URL.Path, "/") {
+		if authorizerAppidPattern.MatchString(segment) {
+			return segment
+		}
+	}
+	return
+}
+
+// parseAuthorizerEvent 解析授权方事件
+func parseAuthorizerEvent(body []byte) (m interface{}, err error) {
+	event := type_event.Event{}
+	err = xml.Unmarshal(body, &event)
+	if err != nil {
+		return
+	}
+
+	switch event.Event {
+
+	// 小程序代码审核事件
+	case type_event.EventTypeWeappAuditSuccess:
+		msg := type_event.EventWeappAuditSuccess{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case type_event.EventTypeWeappAuditFail:
+		msg := type_event.EventWeappAuditFail{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	case type_event.EventTypeWeappAuditDelay:
+		msg := type_event.EventWeappAuditDelay{}
+		err = xml.Unmarshal(body, &msg)
+		if err != nil {
+			return
+		}
+		return msg, nil
+	}
+	return
+}
+
+// decryptXML 如果是加密消息则使用平台 AesKey 解密
+func (s *Server) decryptXML(body []byte) (xmlMsg []byte, err error) {
+	if s.Ctx.Logger != nil {
+		s.Ctx.Logger.Println(string(body))
+	}
+
+	// 是否加密消息
+	encryptMsg := type_message.EncryptMessage{}
+	err = xml.Unmarshal(body, &encryptMsg)
+	if err != nil {
+		return
+	}
+
+	// 不需要解密
+	if encryptMsg.Encrypt == "" {
+		return body, nil
+	}
+
+	_, xmlMsg, _, err = util.AESDecryptMsg(encryptMsg.Encrypt, s.Ctx.Config.AesKey)
+	if err != nil {
+		return
+	}
+
+	if s.Ctx.Logger != nil {
+		s.Ctx.Logger.Println("AESDecryptMsg ", string(xmlMsg))
+	}
+	return
+}
+
 // Response 响应微信消息 (自动判断是否要加密)
 func (s *Server) Response(writer http.ResponseWriter, request *http.Request, reply interface{}) (err error) {
 
 	output := []byte("success") // 默认回复
-	if reply != nil {
+	if _, ok := reply.(emptyReply); ok {
+		output = []byte{}
+	} else if reply != nil {
 		output, err = xml.Marshal(reply)
 		if err != nil {
 			return
@@ -142,20 +404,9 @@ func (s *Server) encryptReplyMessage(rawXmlMsg []byte) (replyEncryptMessage type
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 	nonce := util.GetRandString(6)
 
-	strs := []string{
-		timestamp,
-		nonce,
-		s.Ctx.Config.Token,
-		cipherText,
-	}
-	sort.Strings(strs)
-	h := sha1.New()
-	_, _ = io.WriteString(h, strings.Join(strs, ""))
-	signature := fmt.Sprintf("%x", h.Sum(nil))
-
 	return type_message.ReplyEncryptMessage{
 		Encrypt:      cipherText,
-		MsgSignature: signature,
+		MsgSignature: signature(timestamp, nonce, s.Ctx.Config.Token, cipherText),
 		TimeStamp:    timestamp,
 		Nonce:        nonce,
 	}
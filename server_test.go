@@ -0,0 +1,284 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wxopen
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+	"github.com/fastwego/offiaccount/util"
+	"github.com/fastwego/wxopen/type/type_event"
+	"github.com/fastwego/wxopen/type/type_platform"
+)
+
+func TestServer_ParseXML(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+
+	fastRegister := type_platform.EventNotifyThirdFasteregister{
+		Event:    type_platform.Event{AppId: "第三方平台appid", CreateTime: "1535442403", InfoType: type_platform.EventTypeNotifyThirdFasteregister},
+		Appid:    "创建小程序appid",
+		AuthCode: "xxxxx第三方授权码",
+		Msg:      "OK",
+	}
+	fastRegister.Info.Name = "企业名称"
+	fastRegister.Info.Code = "企业代码"
+	fastRegister.Info.CodeType = 1
+	fastRegister.Info.LegalPersonaWechat = "法人微信号"
+	fastRegister.Info.LegalPersonaName = "法人姓名"
+	fastRegister.Info.ComponentPhone = "第三方联系电话"
+
+	personalRegister := type_platform.EventNotifyThirdFasteregister{
+		Event: type_platform.Event{AppId: "第三方平台appid", CreateTime: "1535442403", InfoType: type_platform.EventTypeNotifyThirdFasteregister},
+		Appid: "创建小程序appid",
+		Msg:   "OK",
+	}
+	personalRegister.Info.Wxuser = "用户微信号"
+	personalRegister.Info.Idname = "用户姓名"
+	personalRegister.Info.ComponentPhone = "第三方联系电话"
+
+	betaRegister := type_platform.EventNotifyThirdFastRegisterBetaApp{
+		Event:  type_platform.Event{AppId: "第三方平台appid", CreateTime: "1535442403", InfoType: type_platform.EventTypeNotifyThirdFastRegisterBetaApp},
+		Appid:  "创建小程序appid",
+		Status: 89251,
+		Msg:    "OK",
+	}
+	betaRegister.Info.UniqueId = "unique_id"
+	betaRegister.Info.Name = "小程序名称"
+
+	tests := []struct {
+		name string
+		body string
+		want interface{}
+	}{
+		{
+			name: "notify_third_fasteregister",
+			body: `<xml>
+    <AppId><![CDATA[第三方平台appid]]></AppId>
+    <CreateTime>1535442403</CreateTime>
+    <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+    <appid>创建小程序appid</appid>
+    <status>0</status>
+    <auth_code>xxxxx第三方授权码</auth_code>
+    <msg>OK</msg>
+    <info>
+    <name><![CDATA[企业名称]]></name>
+    <code><![CDATA[企业代码]]></code>
+    <code_type>1</code_type>
+    <legal_persona_wechat><![CDATA[法人微信号]]></legal_persona_wechat>
+    <legal_persona_name><![CDATA[法人姓名]]></legal_persona_name>
+    <component_phone><![CDATA[第三方联系电话]]></component_phone>
+    </info>
+</xml>`,
+			want: fastRegister,
+		},
+		{
+			name: "notify_third_fasteregister personal",
+			body: `<xml>
+    <AppId><![CDATA[第三方平台appid]]></AppId>
+    <CreateTime>1535442403</CreateTime>
+    <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+    <appid>创建小程序appid</appid>
+    <status>0</status>
+    <msg>OK</msg>
+    <info>
+    <wxuser><![CDATA[用户微信号]]></wxuser>
+    <idname><![CDATA[用户姓名]]></idname>
+    <component_phone><![CDATA[第三方联系电话]]></component_phone>
+    </info>
+</xml>`,
+			want: personalRegister,
+		},
+		{
+			name: "notify_third_fastregisterbetaapp",
+			body: `<xml>
+    <AppId><![CDATA[第三方平台appid]]></AppId>
+    <CreateTime>1535442403</CreateTime>
+    <InfoType><![CDATA[notify_third_fastregisterbetaapp]]></InfoType>
+    <appid>创建小程序appid</appid>
+    <status>89251</status>
+    <msg>OK</msg>
+    <info>
+    <unique_id><![CDATA[unique_id]]></unique_id>
+    <name><![CDATA[小程序名称]]></name>
+    </info>
+</xml>`,
+			want: betaRegister,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := platform.Server.ParseXML([]byte(tt.body))
+			if err != nil {
+				t.Errorf("ParseXML() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseXML() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ParseAuthorizerXML(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{AppId: "APPID"})
+	platform.Logger = nil
+
+	header := `<ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName><FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName><CreateTime>1488856741</CreateTime><MsgType><![CDATA[event]]></MsgType>`
+	event := func(name string) type_event.Event {
+		e := type_event.Event{Event: name}
+		e.ToUserName = "gh_fb9688c2a4b2"
+		e.FromUserName = "od1P50M-fNQI5Gcq-trm4a7apsU8"
+		e.CreateTime = "1488856741"
+		e.MsgType = "event"
+		if name == "" {
+			e.MsgType = "text"
+		}
+		return e
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "weapp_audit_success",
+			body: `<xml>` + header + `<Event><![CDATA[weapp_audit_success]]></Event><SuccTime>1488856741</SuccTime></xml>`,
+			want: type_event.EventWeappAuditSuccess{Event: event("weapp_audit_success"), SuccTime: "1488856741"},
+		},
+		{
+			name: "weapp_audit_fail",
+			body: `<xml>` + header + `<Event><![CDATA[weapp_audit_fail]]></Event><Reason><![CDATA[reason]]></Reason><FailTime>1488856591</FailTime><ScreenShot><![CDATA[xxx|yyy]]></ScreenShot></xml>`,
+			want: type_event.EventWeappAuditFail{Event: event("weapp_audit_fail"), Reason: "reason", FailTime: "1488856591", ScreenShot: "xxx|yyy"},
+		},
+		{
+			name: "weapp_audit_delay",
+			body: `<xml>` + header + `<Event><![CDATA[weapp_audit_delay]]></Event><Reason><![CDATA[reason]]></Reason><DelayTime>1488856591</DelayTime></xml>`,
+			want: type_event.EventWeappAuditDelay{Event: event("weapp_audit_delay"), Reason: "reason", DelayTime: "1488856591"},
+		},
+		{
+			name: "text",
+			body: `<xml><ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName><FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName><CreateTime>1488856741</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content><MsgId>1234567890123456</MsgId></xml>`,
+			want: type_message.MessageText{Message: event("").Message, MsgId: "1234567890123456", Content: "hello"},
+		},
+		{
+			name:    "invalid",
+			body:    `<xml>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := platform.Server.ParseAuthorizerXML([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAuthorizerXML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAuthorizerXML() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ServeAuthorizerMessage(t *testing.T) {
+	platform := NewPlatform(PlatformConfig{
+		AppId:  "APPID",
+		Token:  "TOKEN",
+		AesKey: "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG",
+	})
+	platform.Logger = nil
+
+	reply := func(content string) AuthorizerMessageHandlerFunc {
+		return func(appid string, message interface{}) (interface{}, error) {
+			msg := message.(type_message.MessageText)
+			return type_message.ReplyMessageText{
+				ReplyMessage: type_message.ReplyMessage{
+					ToUserName:   type_message.CDATA(msg.FromUserName),
+					FromUserName: type_message.CDATA(msg.ToUserName),
+					CreateTime:   msg.CreateTime,
+					MsgType:      type_message.ReplyMsgTypeText,
+				},
+				Content: type_message.CDATA(content + ":" + appid + ":" + msg.Content),
+			}, nil
+		}
+	}
+	platform.Server.HandleAuthorizer("wx570bc396a51b8ff8", reply("single"))
+	platform.Server.HandleAllAuthorizers(reply("all"))
+
+	message := `<xml><ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName><FromUserName><![CDATA[openid]]></FromUserName><CreateTime>1488856741</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hello]]></Content><MsgId>1</MsgId></xml>`
+	cipherText := util.AESEncryptMsg([]byte(util.GetRandString(16)), []byte(message), platform.Config.AppId, platform.Config.AesKey)
+	encrypted := `<xml><ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName><Encrypt><![CDATA[` + cipherText + `]]></Encrypt></xml>`
+	encryptedQuery := "?encrypt_type=aes&timestamp=1488856741&nonce=NONCE&msg_signature=" + signature("TOKEN", "1488856741", "NONCE", cipherText)
+	plainQuery := "?timestamp=1488856741&nonce=NONCE&signature=" + signature("TOKEN", "1488856741", "NONCE")
+
+	tests := []struct {
+		name    string
+		target  string
+		body    string
+		plain   bool // 未配置 AesKey
+		want    string
+		wantErr error
+	}{
+		{name: "single", target: "/wxopen/wx570bc396a51b8ff8/callback" + encryptedQuery, body: encrypted, want: "single:wx570bc396a51b8ff8:hello"},
+		{name: "all", target: "/wxopen/wxd101a85aa106f53e/callback" + encryptedQuery, body: encrypted, want: "all:wxd101a85aa106f53e:hello"},
+		{name: "plain", target: "/wxopen/wx570bc396a51b8ff8/callback" + plainQuery, body: message, plain: true, want: "single:wx570bc396a51b8ff8:hello"},
+		{name: "plain rejected", target: "/wxopen/wx570bc396a51b8ff8/callback" + plainQuery, body: message, wantErr: ErrorPlaintextMessage},
+		{name: "bad signature", target: "/wxopen/wx570bc396a51b8ff8/callback?encrypt_type=aes&timestamp=1488856741&nonce=NONCE&msg_signature=bad", body: encrypted, wantErr: ErrorInvalidSignature},
+		{name: "bad plain signature", target: "/wxopen/wx570bc396a51b8ff8/callback?timestamp=1488856741&nonce=NONCE&signature=bad", body: message, plain: true, wantErr: ErrorInvalidSignature},
+		{name: "no appid", target: "/wxopen/callback" + encryptedQuery, body: encrypted, wantErr: ErrorAuthorizerAppidNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform.Config.AesKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+			if tt.plain {
+				platform.Config.AesKey = ""
+			}
+			request := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+			err := platform.Server.ServeAuthorizerMessage(recorder, request)
+			if err != tt.wantErr {
+				t.Errorf("ServeAuthorizerMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			output := recorder.Body.Bytes()
+			if request.URL.Query().Get("encrypt_type") == "aes" {
+				encryptReply := type_message.ReplyEncryptMessage{}
+				_ = xml.Unmarshal(output, &encryptReply)
+				_, output, _, err = util.AESDecryptMsg(encryptReply.Encrypt, platform.Config.AesKey)
+				if err != nil {
+					t.Errorf("AESDecryptMsg() error = %v", err)
+					return
+				}
+			}
+
+			got := struct{ Content string }{}
+			_ = xml.Unmarshal(output, &got)
+			if got.Content != tt.want {
+				t.Errorf("ServeAuthorizerMessage() reply = %s, want %s", output, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/fastwego/wxopen"
+)
+
+/*
+Fixture 录制的接口请求/响应
+
+生成的测试从 testdata/<方法名>.json 读取，以 Query 和 Body 发起请求，模拟服务器返回 Response
+
+返回图片等二进制内容的接口，ContentType 为响应类型，Response 为 base64 编码的响应体
+*/
+type Fixture struct {
+	Query       url.Values      `json:"query,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// LoadFixture 读取 fixture 文件
+func LoadFixture(filename string) (fixture Fixture, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &fixture)
+	return
+}
+
+// ResponseBody 模拟服务器返回的响应体
+func (fixture Fixture) ResponseBody() (body []byte, err error) {
+	if fixture.ContentType == "" {
+		return fixture.Response, nil
+	}
+	err = json.Unmarshal(fixture.Response, &body)
+	return
+}
+
+// Request 模拟服务器收到的请求
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+/*
+FormFiles 解析 multipart 请求中上传的文件
+
+返回表单字段名 => 文件名
+*/
+func (req Request) FormFiles() (files map[string]string, err error) {
+	form, err := req.multipartForm()
+	if err != nil {
+		return
+	}
+
+	files = map[string]string{}
+	for name, headers := range form.File {
+		files[name] = headers[0].Filename
+	}
+	return
+}
+
+// FormValues 解析 multipart 请求中的非文件表单字段
+func (req Request) FormValues() (values url.Values, err error) {
+	form, err := req.multipartForm()
+	if err != nil {
+		return
+	}
+	return url.Values(form.Value), nil
+}
+
+func (req Request) multipartForm() (form *multipart.Form, err error) {
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return
+	}
+	return multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(int64(len(req.Body)) + 1024)
+}
+
+// ErrorReader 读取时总是返回 Err，用于测试上传文件读取出错
+type ErrorReader struct {
+	Err error
+}
+
+func (reader ErrorReader) Read(p []byte) (n int, err error) {
+	return 0, reader.Err
+}
+
+// FailAccessToken 让平台及授权方实例获取 access_token 时返回 err，模拟请求发出前出错；返回恢复函数
+func FailAccessToken(err error) (restore func()) {
+	getComponentAccessToken := MockPlatform.GetComponentAccessTokenHandler
+	getAuthorizerAccessToken := MockPlatform.GetAuthorizerAccessTokenHandler
+	MockPlatform.GetComponentAccessTokenHandler = func(platform *wxopen.Platform) (string, error) {
+		return "", err
+	}
+	MockPlatform.GetAuthorizerAccessTokenHandler = func(platform *wxopen.Platform, appid string) (string, error) {
+		return "", err
+	}
+	return func() {
+		MockPlatform.GetComponentAccessTokenHandler = getComponentAccessToken
+		MockPlatform.GetAuthorizerAccessTokenHandler = getAuthorizerAccessToken
+	}
+}
+
+// GoroutinesSettled 等待 goroutine 数量回落到 n 以内，1 秒后仍然超出时返回 false
+func GoroutinesSettled(n int) bool {
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if runtime.NumGoroutine() <= n {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+	}
+}
+
+/*
+MockApi 模拟一个微信接口：依次返回预设的响应，并记录收到的请求
+
+响应用完后重复返回最后一个；与微信服务器一致，json 响应的 Content-Type 为 application/json，其他按内容识别
+*/
+type MockApi struct {
+	lock      sync.Mutex
+	responses [][]byte
+	requests  []Request
+}
+
+var mockApis = map[string]*MockApi{}
+var mockApisLock sync.Mutex
+
+// HandleMockApi 在模拟服务器上注册 path 对应的 MockApi，已注册时返回同一个 MockApi (测试与示例共用)
+func HandleMockApi(path string) (mock *MockApi) {
+	mockApisLock.Lock()
+	defer mockApisLock.Unlock()
+
+	if mock, ok := mockApis[path]; ok {
+		return mock
+	}
+	mock = &MockApi{}
+	MockSvrHandler.HandleFunc(path, mock.ServeHTTP)
+	mockApis[path] = mock
+	return
+}
+
+// Reset 清空请求记录，并设置接下来依次返回的响应
+func (mock *MockApi) Reset(responses ...[]byte) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	mock.responses = responses
+	mock.requests = nil
+}
+
+// Requests 返回收到的请求
+func (mock *MockApi) Requests() []Request {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	return append([]Request(nil), mock.requests...)
+}
+
+func (mock *MockApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	mock.lock.Lock()
+	mock.requests = append(mock.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+	})
+	var resp []byte
+	if len(mock.responses) > 0 {
+		resp = mock.responses[0]
+		if len(mock.responses) > 1 {
+			mock.responses = mock.responses[1:]
+		}
+	}
+	mock.lock.Unlock()
+
+	if json.Valid(resp) {
+		w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+	} else {
+		w.Header().Set("Content-Type", http.DetectContentType(resp))
+	}
+	_, _ = w.Write(resp)
+}
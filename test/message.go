@@ -0,0 +1,60 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/fastwego/offiaccount/type/type_message"
+	"github.com/fastwego/offiaccount/util"
+)
+
+/*
+NewAuthorizerMessageRequest 模拟微信服务器推送到 target 的授权方消息
+
+消息使用 MockPlatform 的 AesKey 加密，并以 Token 签名
+*/
+func NewAuthorizerMessageRequest(target string, message string) *http.Request {
+	cipherText := util.AESEncryptMsg([]byte(util.GetRandString(16)), []byte(message), MockPlatform.Config.AppId, MockPlatform.Config.AesKey)
+	body := `<xml><ToUserName><![CDATA[gh_3c884a361561]]></ToUserName><Encrypt><![CDATA[` + cipherText + `]]></Encrypt></xml>`
+
+	timestamp, nonce := "1488856741", util.GetRandString(6)
+	strs := []string{MockPlatform.Config.Token, timestamp, nonce, cipherText}
+	sort.Strings(strs)
+	query := url.Values{}
+	query.Set("encrypt_type", "aes")
+	query.Set("timestamp", timestamp)
+	query.Set("nonce", nonce)
+	query.Set("msg_signature", fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(strs, "")))))
+
+	return httptest.NewRequest(http.MethodPost, target+"?"+query.Encode(), strings.NewReader(body))
+}
+
+// DecryptReply 解密被动回复的消息，未加密的回复原样返回
+func DecryptReply(reply []byte) (message []byte, err error) {
+	encryptReply := type_message.ReplyEncryptMessage{}
+	if xml.Unmarshal(reply, &encryptReply) != nil || encryptReply.Encrypt == "" {
+		return reply, nil
+	}
+	_, message, _, err = util.AESDecryptMsg(encryptReply.Encrypt, MockPlatform.Config.AesKey)
+	return
+}
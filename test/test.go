@@ -20,10 +20,14 @@ import (
 	"net/http/httptest"
 	"sync"
 
+	"github.com/fastwego/miniprogram"
+	"github.com/fastwego/offiaccount"
 	"github.com/fastwego/wxopen"
 )
 
 var MockPlatform *wxopen.Platform
+var MockOffiAccount *offiaccount.OffiAccount
+var MockMiniprogram *miniprogram.Miniprogram
 var MockSvr *httptest.Server
 var MockSvrHandler *http.ServeMux
 var onceSetup sync.Once
@@ -36,13 +40,15 @@ func Setup() {
 			AppId:     "APPID",
 			AppSecret: "SECRET",
 			Token:     "TOKEN",
-			AesKey:    "AesKey",
+			AesKey:    "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG",
 		})
 
 		// Mock Server
 		MockSvrHandler = http.NewServeMux()
 		MockSvr = httptest.NewServer(MockSvrHandler)
 		wxopen.WXServerUrl = MockSvr.URL // 拦截发往微信服务器的请求
+		offiaccount.WXServerUrl = MockSvr.URL
+		miniprogram.WXServerUrl = MockSvr.URL
 
 		// Mock Ticket
 		_ = MockPlatform.ReceiveComponentVerifyTicketHandler(MockPlatform, "TICKET")
@@ -51,5 +57,15 @@ func Setup() {
 		MockSvrHandler.HandleFunc("/cgi-bin/component/api_component_token", func(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write([]byte(`{"component_access_token":"ACCESS_TOKEN","expires_in":7200}`))
 		})
+
+		// Mock 授权方公众号/小程序实例
+		_ = MockPlatform.Cache.Save("authorizer_access_token:AUTHORIZER_APPID", "AUTHORIZER_ACCESS_TOKEN", 0)
+
+		// Mock 刷新 authorizer_access_token
+		MockPlatform.NoticeAuthorizerAccessTokenExpireHandler = func(platform *wxopen.Platform, appid string) (err error) {
+			return platform.Cache.Save("authorizer_access_token:"+appid, "AUTHORIZER_ACCESS_TOKEN", 0)
+		}
+		MockOffiAccount, _ = MockPlatform.NewOffiAccount("AUTHORIZER_APPID")
+		MockMiniprogram, _ = MockPlatform.NewMiniprogram("AUTHORIZER_APPID")
 	})
 }
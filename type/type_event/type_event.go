@@ -0,0 +1,77 @@
+// Copyright 2020 FastWeGo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package type_event 推送到消息与事件接收 URL 的授权方事件
+package type_event
+
+import "github.com/fastwego/offiaccount/type/type_message"
+
+const (
+	EventTypeWeappAuditSuccess = "weapp_audit_success" // 小程序代码审核通过
+	EventTypeWeappAuditFail    = "weapp_audit_fail"    // 小程序代码审核不通过
+	EventTypeWeappAuditDelay   = "weapp_audit_delay"   // 小程序代码审核延后
+)
+
+type Event type_message.MessageEvent
+
+/*
+<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856741</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_success]]></Event>
+  <SuccTime>1488856741</SuccTime>
+</xml>
+*/
+type EventWeappAuditSuccess struct {
+	Event
+	SuccTime string
+}
+
+/*
+<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856591</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_fail]]></Event>
+  <Reason><![CDATA[1:账号信息不符合规范:<br>(1):包含色情因素<br>2:服务类目"金融业-保险_"与你提交代码审核时设置的功能页面内容不一致:<br>(1):功能页面设置的部分标签不属于所选的服务类目范围。<br>(2):功能页面设置的部分标签与该页面内容不相关。<br>]]></Reason>
+  <FailTime>1488856591</FailTime>
+  <ScreenShot><![CDATA[xxx|yyy|zzz]]></ScreenShot>
+</xml>
+*/
+type EventWeappAuditFail struct {
+	Event
+	Reason     string
+	FailTime   string
+	ScreenShot string // 审核不通过的截图示例，用 | 分隔的 media_id 列表
+}
+
+/*
+<xml>
+  <ToUserName><![CDATA[gh_fb9688c2a4b2]]></ToUserName>
+  <FromUserName><![CDATA[od1P50M-fNQI5Gcq-trm4a7apsU8]]></FromUserName>
+  <CreateTime>1488856591</CreateTime>
+  <MsgType><![CDATA[event]]></MsgType>
+  <Event><![CDATA[weapp_audit_delay]]></Event>
+  <Reason><![CDATA[为了更好的服务小程序，您的服务商正在进行提审系统的优化，可能会导致审核时效的增长，请耐心等待]]></Reason>
+  <DelayTime>1488856591</DelayTime>
+</xml>
+*/
+type EventWeappAuditDelay struct {
+	Event
+	Reason    string
+	DelayTime string
+}
@@ -23,6 +23,9 @@ const (
 	EventTypeAuthorized            = "authorized"
 	EventTypeUnauthorized          = "unauthorized"
 	EventTypeUpdateAuthorized      = "updateauthorized"
+
+	EventTypeNotifyThirdFasteregister       = "notify_third_fasteregister"       // 快速创建小程序(企业/个人) 结果通知
+	EventTypeNotifyThirdFastRegisterBetaApp = "notify_third_fastregisterbetaapp" // 创建试用小程序结果通知
 )
 
 type Event struct {
@@ -98,3 +101,88 @@ type EventUpdateAuthorized struct {
 	AuthorizationCodeExpiredTime string
 	PreAuthCode                  string
 }
+
+/*
+快速创建小程序结果通知
+
+企业小程序：
+<xml>
+  <AppId><![CDATA[第三方平台appid]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+  <appid>创建小程序appid</appid>
+  <status>0</status>
+  <auth_code>xxxxx第三方授权码</auth_code>
+  <msg>OK</msg>
+  <info>
+    <name><![CDATA[企业名称]]></name>
+    <code><![CDATA[企业代码]]></code>
+    <code_type>1</code_type>
+    <legal_persona_wechat><![CDATA[法人微信号]]></legal_persona_wechat>
+    <legal_persona_name><![CDATA[法人姓名]]></legal_persona_name>
+    <component_phone><![CDATA[第三方联系电话]]></component_phone>
+  </info>
+</xml>
+
+个人小程序：
+<xml>
+  <AppId><![CDATA[第三方平台appid]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fasteregister]]></InfoType>
+  <appid>创建小程序appid</appid>
+  <status>0</status>
+  <msg>OK</msg>
+  <info>
+    <wxuser><![CDATA[用户微信号]]></wxuser>
+    <idname><![CDATA[用户姓名]]></idname>
+    <component_phone><![CDATA[第三方联系电话]]></component_phone>
+  </info>
+</xml>
+*/
+type EventNotifyThirdFasteregister struct {
+	Event
+	Appid    string `xml:"appid"`
+	Status   int    `xml:"status"`
+	AuthCode string `xml:"auth_code"`
+	Msg      string `xml:"msg"`
+	Info     struct {
+		// 企业小程序
+		Name               string `xml:"name"`
+		Code               string `xml:"code"`
+		CodeType           int    `xml:"code_type"`
+		LegalPersonaWechat string `xml:"legal_persona_wechat"`
+		LegalPersonaName   string `xml:"legal_persona_name"`
+
+		// 个人小程序
+		Wxuser string `xml:"wxuser"`
+		Idname string `xml:"idname"`
+
+		ComponentPhone string `xml:"component_phone"`
+	} `xml:"info"`
+}
+
+/*
+创建试用小程序结果通知
+<xml>
+  <AppId><![CDATA[第三方平台appid]]></AppId>
+  <CreateTime>1535442403</CreateTime>
+  <InfoType><![CDATA[notify_third_fastregisterbetaapp]]></InfoType>
+  <appid>创建小程序appid</appid>
+  <status>0</status>
+  <msg>OK</msg>
+  <info>
+    <unique_id><![CDATA[unique_id]]></unique_id>
+    <name><![CDATA[小程序名称]]></name>
+  </info>
+</xml>
+*/
+type EventNotifyThirdFastRegisterBetaApp struct {
+	Event
+	Appid  string `xml:"appid"`
+	Status int    `xml:"status"`
+	Msg    string `xml:"msg"`
+	Info   struct {
+		UniqueId string `xml:"unique_id"`
+		Name     string `xml:"name"`
+	} `xml:"info"`
+}
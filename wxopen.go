@@ -26,7 +26,9 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -56,6 +58,12 @@ type GetAuthorizerAccessTokenFunc func(platform *Platform, appid string) (author
 // NoticeAuthorizerAccessTokenExpireFunc 通知刷新 AuthorizerAccessToken 方法接口
 type NoticeAuthorizerAccessTokenExpireFunc func(platform *Platform, appid string) (err error)
 
+// GetAuthorizerAppidFunc 从消息与事件接收 URL 中获取授权方 appid 方法接口
+type GetAuthorizerAppidFunc func(platform *Platform, request *http.Request) (appid string)
+
+// RecordApiCallFunc 记录接口调用方法接口 (appid 为调用方：平台 / 授权方 / 网站应用等)
+type RecordApiCallFunc func(platform *Platform, appid string, path string)
+
 /*
 PlatformConfig 平台 配置
 */
@@ -84,6 +92,15 @@ type Platform struct {
 
 	GetAuthorizerAccessTokenHandler          GetAuthorizerAccessTokenFunc
 	NoticeAuthorizerAccessTokenExpireHandler NoticeAuthorizerAccessTokenExpireFunc
+
+	GetAuthorizerAppidHandler GetAuthorizerAppidFunc
+
+	// 可选：记录每个 appid 每个接口的调用，用于排查 quota 消耗，默认不记录
+	// 重试及 NewOffiAccount / NewMiniprogram 实例的请求同样记录
+	RecordApiCallHandler RecordApiCallFunc
+
+	// 可选：本地限流，请求发出前按 appid + 接口路径获取令牌，默认不限流
+	RateLimiter *RateLimiter
 }
 
 /*
@@ -102,6 +119,8 @@ func NewPlatform(config PlatformConfig) (platform *Platform) {
 
 		GetAuthorizerAccessTokenHandler:          GetAuthorizerAccessToken,
 		NoticeAuthorizerAccessTokenExpireHandler: NoticeAuthorizerAccessTokenExpire,
+
+		GetAuthorizerAppidHandler: GetAuthorizerAppid,
 	}
 
 	instance.Client = Client{Ctx: &instance}
@@ -123,14 +142,23 @@ func (platform *Platform) NewOffiAccount(appid string) (offiAccount *offiaccount
 		EncodingAESKey: platform.Config.AesKey,
 	})
 
+	// 实例请求经过 instanceTransport 限流、记录接口调用，按 access_token 识别调用方
+	hookInstances()
 	offiAccount.AccessToken.GetAccessTokenHandler = func(ctx *offiaccount.OffiAccount) (accessToken string, err error) {
-		return platform.GetAuthorizerAccessTokenHandler(platform, ctx.Config.Appid)
+		accessToken, err = platform.GetAuthorizerAccessTokenHandler(platform, ctx.Config.Appid)
+		if err == nil {
+			instanceHook.register(platform, ctx.Config.Appid, accessToken)
+		}
+		return
 	}
 
 	offiAccount.AccessToken.NoticeAccessTokenExpireHandler = func(ctx *offiaccount.OffiAccount) (err error) {
 		return platform.NoticeAuthorizerAccessTokenExpireHandler(platform, ctx.Config.Appid)
 	}
 
+	// 请求日志隐藏 token 后写入平台 Logger
+	offiAccount.Logger = log.New(&instanceLogWriter{platform: platform}, "", 0)
+
 	return
 }
 
@@ -143,17 +171,52 @@ func (platform *Platform) NewMiniprogram(appid string) (mini *miniprogram.Minipr
 		Secret: "",
 	})
 
+	// 实例请求经过 instanceTransport 限流、记录接口调用，按 access_token 识别调用方
+	hookInstances()
 	mini.AccessToken.GetAccessTokenHandler = func(ctx *miniprogram.Miniprogram) (accessToken string, err error) {
-		return platform.GetAuthorizerAccessTokenHandler(platform, ctx.Config.Appid)
+		accessToken, err = platform.GetAuthorizerAccessTokenHandler(platform, ctx.Config.Appid)
+		if err == nil {
+			instanceHook.register(platform, ctx.Config.Appid, accessToken)
+		}
+		return
 	}
 
 	mini.AccessToken.NoticeAccessTokenExpireHandler = func(ctx *miniprogram.Miniprogram) (err error) {
 		return platform.NoticeAuthorizerAccessTokenExpireHandler(platform, ctx.Config.Appid)
 	}
 
+	// 请求日志隐藏 token 后写入平台 Logger
+	mini.Logger = log.New(&instanceLogWriter{platform: platform}, "", 0)
+
 	return
 }
 
+/*
+instanceLogWriter 把授权方实例的日志写入平台 Logger
+
+fastwego/offiaccount、fastwego/miniprogram 的请求日志带有 access_token，写入前隐藏
+*/
+type instanceLogWriter struct {
+	platform *Platform
+}
+
+func (w *instanceLogWriter) Write(p []byte) (n int, err error) {
+	line := strings.TrimSuffix(string(p), "\n")
+
+	// 请求日志格式：[错误 retry ]METHOD URL Headers map[...]
+	if i := strings.Index(line, " Headers "); i >= 0 {
+		start := strings.LastIndex(line[:i], " ") + 1
+		if u, err := url.Parse(line[start:i]); err == nil && u.Host != "" {
+			line = line[:start] + redactUrl(u) + line[i:]
+		}
+	}
+
+	if w.platform.Logger != nil {
+		w.platform.Logger.Println(line)
+	}
+	return len(p), nil
+}
+
 /*
 GetAuthorizerAccessToken 获取 authorizer_access_token
 